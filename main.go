@@ -18,6 +18,7 @@ func main() {
 	}
 
 	db.ConnectDatabase()
+	db.StartBackgroundJobs()
 
 	router := gin.Default()
 
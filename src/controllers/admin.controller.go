@@ -0,0 +1,152 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+
+	"recipe-api/src/db"
+	"recipe-api/src/models"
+	"recipe-api/src/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+func BanUser(c *gin.Context) {
+	setUserBanned(c, true)
+}
+
+func UnbanUser(c *gin.Context) {
+	setUserBanned(c, false)
+}
+
+func setUserBanned(c *gin.Context, banned bool) {
+	id := c.Param("id")
+	var user models.User
+
+	if err := db.DB.First(&user, "id = ?", id).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "User not found")
+		return
+	}
+
+	if actor := currentUser(c); actor != nil && actor.ID == user.ID {
+		utils.ErrorResponse(c, http.StatusBadRequest, "You cannot change your own ban status")
+		return
+	}
+
+	var bannedAt *time.Time
+	if banned {
+		now := time.Now()
+		bannedAt = &now
+	}
+
+	result := db.DB.Model(&user).Updates(map[string]interface{}{
+		"banned":    banned,
+		"banned_at": bannedAt,
+	})
+	if result.Error != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError,
+			"Failed to update user: "+result.Error.Error())
+		return
+	}
+
+	message := "User banned"
+	if !banned {
+		message = "User unbanned"
+	}
+	utils.SuccessResponse(c, http.StatusOK, message, user)
+}
+
+func SetUserRole(c *gin.Context) {
+	id := c.Param("id")
+	var user models.User
+
+	if err := db.DB.First(&user, "id = ?", id).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "User not found")
+		return
+	}
+
+	var input struct {
+		Role string `json:"role" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body: "+err.Error())
+		return
+	}
+	if !models.IsValidRole(input.Role) {
+		utils.ErrorResponse(c, http.StatusBadRequest,
+			"Unknown role. Valid roles are user, moderator and admin")
+		return
+	}
+
+	if err := db.DB.Model(&user).Update("role", input.Role).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError,
+			"Failed to update role: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "User role updated", user)
+}
+
+// passwordResetTTL is how long an issued password reset token stays valid.
+const passwordResetTTL = 24 * time.Hour
+
+// IssuePasswordReset creates a one-time token the user can exchange for a new
+// password at POST /api/auth/reset-password. It is the way in for accounts
+// that predate password hashing and so have no password at all. Issuing a
+// new token replaces any earlier one.
+func IssuePasswordReset(c *gin.Context) {
+	id := c.Param("id")
+	var user models.User
+
+	if err := db.DB.First(&user, "id = ?", id).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "User not found")
+		return
+	}
+
+	token, err := utils.GenerateResetToken()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	expiresAt := time.Now().Add(passwordResetTTL)
+
+	result := db.DB.Model(&user).Updates(map[string]interface{}{
+		"reset_token_hash": utils.HashResetToken(token),
+		"reset_expires_at": expiresAt,
+	})
+	if result.Error != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError,
+			"Failed to issue password reset: "+result.Error.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated,
+		"Password reset issued. Give the token to the user; it works once 🔑", gin.H{
+			"user_id":    user.ID,
+			"token":      token,
+			"expires_at": expiresAt,
+		})
+}
+
+func GetStats(c *gin.Context) {
+	var stats struct {
+		Users         int64   `json:"users"`
+		BannedUsers   int64   `json:"banned_users"`
+		Recipes       int64   `json:"recipes"`
+		Ratings       int64   `json:"ratings"`
+		HiddenRatings int64   `json:"hidden_ratings"`
+		AverageRating float64 `json:"average_rating"`
+	}
+
+	db.DB.Model(&models.User{}).Count(&stats.Users)
+	db.DB.Model(&models.User{}).Where("banned = ?", true).Count(&stats.BannedUsers)
+	db.DB.Model(&models.Recipe{}).Count(&stats.Recipes)
+	db.DB.Model(&models.Rating{}).Count(&stats.Ratings)
+	db.DB.Model(&models.Rating{}).Where("hidden = ?", true).Count(&stats.HiddenRatings)
+	db.DB.Model(&models.Rating{}).
+		Select("COALESCE(AVG(score), 0)").
+		Where("hidden = ?", false).
+		Scan(&stats.AverageRating)
+
+	utils.SuccessResponse(c, http.StatusOK, "Stats fetched successfully", stats)
+}
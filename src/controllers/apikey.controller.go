@@ -0,0 +1,103 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"recipe-api/src/db"
+	"recipe-api/src/models"
+	"recipe-api/src/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+func CreateAPIKey(c *gin.Context) {
+	if _, usingKey := c.Get("apiKey"); usingKey {
+		utils.ErrorResponse(c, http.StatusForbidden, "API keys cannot be used to manage API keys")
+		return
+	}
+
+	var input models.CreateAPIKeyInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest,
+			"Invalid API key data. Name and at least one scope are required: "+err.Error())
+		return
+	}
+
+	for _, scope := range input.Scopes {
+		if !models.ValidScopes[scope] {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Unknown scope: "+scope)
+			return
+		}
+	}
+
+	plaintext, prefix, err := utils.GenerateAPIKey()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	key := models.APIKey{
+		UserID:  currentUser(c).ID,
+		Name:    input.Name,
+		Prefix:  prefix,
+		KeyHash: utils.HashAPIKey(plaintext),
+		Scopes:  strings.Join(input.Scopes, ","),
+	}
+
+	if err := db.DB.Create(&key).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError,
+			"Failed to create API key: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated,
+		"API key created. Store it now — it will not be shown again 🔐", gin.H{
+			"key":     plaintext,
+			"api_key": key,
+		})
+}
+
+func GetAPIKeys(c *gin.Context) {
+	var keys []models.APIKey
+	result := db.DB.Where("user_id = ?", currentUser(c).ID).
+		Order("created_at DESC").
+		Find(&keys)
+
+	if result.Error != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError,
+			"Failed to fetch API keys: "+result.Error.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "API keys fetched successfully", keys)
+}
+
+func RevokeAPIKey(c *gin.Context) {
+	if _, usingKey := c.Get("apiKey"); usingKey {
+		utils.ErrorResponse(c, http.StatusForbidden, "API keys cannot be used to manage API keys")
+		return
+	}
+
+	id := c.Param("id")
+	var key models.APIKey
+
+	if err := db.DB.First(&key, "id = ? AND user_id = ?", id, currentUser(c).ID).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "API key not found")
+		return
+	}
+
+	if key.RevokedAt != nil {
+		utils.ErrorResponse(c, http.StatusConflict, "API key is already revoked")
+		return
+	}
+
+	if err := db.DB.Model(&key).Update("revoked_at", time.Now()).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError,
+			"Failed to revoke API key: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "API key revoked", key)
+}
@@ -0,0 +1,98 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+
+	"recipe-api/src/db"
+	"recipe-api/src/models"
+	"recipe-api/src/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+func Login(c *gin.Context) {
+	var input models.LoginInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest,
+			"Invalid login data. Username and password are required: "+err.Error())
+		return
+	}
+
+	var user models.User
+	if err := db.DB.Where("username = ?", input.Username).First(&user).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid username or password")
+		return
+	}
+
+	if !utils.CheckPassword(user.PasswordHash, input.Password) {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid username or password")
+		return
+	}
+
+	if user.Banned {
+		utils.ErrorResponse(c, http.StatusForbidden, "This account has been banned")
+		return
+	}
+
+	token, expiresAt, err := utils.GenerateToken(user.ID, user.Username)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError,
+			"Failed to issue token: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Logged in successfully 🔑", gin.H{
+		"access_token": token,
+		"token_type":   "Bearer",
+		"expires_at":   expiresAt,
+		"user":         user,
+	})
+}
+
+// ResetPassword sets a new password with a token from IssuePasswordReset.
+// The token is cleared on use.
+func ResetPassword(c *gin.Context) {
+	var input models.ResetPasswordInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest,
+			"Invalid reset data. Token and a password of at least 8 characters are required: "+err.Error())
+		return
+	}
+
+	var user models.User
+	err := db.DB.Where("reset_token_hash = ? AND reset_expires_at > ?", utils.HashResetToken(input.Token), time.Now()).
+		First(&user).Error
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid or expired reset token")
+		return
+	}
+
+	hash, err := utils.HashPassword(input.Password)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	result := db.DB.Model(&user).Updates(map[string]interface{}{
+		"password_hash":    hash,
+		"reset_token_hash": "",
+		"reset_expires_at": nil,
+	})
+	if result.Error != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError,
+			"Failed to reset password: "+result.Error.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Password updated. You can log in now 🔑", nil)
+}
+
+func currentUser(c *gin.Context) *models.User {
+	if value, exists := c.Get("currentUser"); exists {
+		if user, ok := value.(*models.User); ok {
+			return user
+		}
+	}
+	return nil
+}
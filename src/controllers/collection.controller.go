@@ -0,0 +1,469 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"recipe-api/src/db"
+	"recipe-api/src/models"
+	"recipe-api/src/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func CreateCollection(c *gin.Context) {
+	var input models.CollectionInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest,
+			"Invalid collection data. Name is required: "+err.Error())
+		return
+	}
+
+	if input.Visibility == "" {
+		input.Visibility = models.VisibilityPrivate
+	}
+	if !models.IsValidCollectionVisibility(input.Visibility) {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid visibility. Use one of: private, public")
+		return
+	}
+
+	collection := models.Collection{
+		UserID:      currentUser(c).ID,
+		Name:        input.Name,
+		Description: input.Description,
+		Visibility:  input.Visibility,
+	}
+	if err := db.DB.Create(&collection).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError,
+			"Failed to create collection: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Collection created successfully! 📚", collection)
+}
+
+// GetCollection returns a collection with its recipes in order. Recipes the
+// caller may not open, or that are in the trash, are left out.
+func GetCollection(c *gin.Context) {
+	collection, ok := findCollection(c)
+	if !ok {
+		return
+	}
+
+	if err := loadCollectionItems(&collection, currentUser(c)); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError,
+			"Failed to fetch collection recipes: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Collection fetched successfully", collection)
+}
+
+func UpdateCollection(c *gin.Context) {
+	collection, ok := findOwnCollection(c)
+	if !ok {
+		return
+	}
+
+	var input models.CollectionInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest,
+			"Invalid collection data. Name is required: "+err.Error())
+		return
+	}
+
+	if input.Visibility == "" {
+		input.Visibility = collection.Visibility
+	}
+	if !models.IsValidCollectionVisibility(input.Visibility) {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid visibility. Use one of: private, public")
+		return
+	}
+
+	result := db.DB.Model(&collection).Updates(map[string]interface{}{
+		"name":        input.Name,
+		"description": input.Description,
+		"visibility":  input.Visibility,
+	})
+	if result.Error != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError,
+			"Failed to update collection: "+result.Error.Error())
+		return
+	}
+
+	db.DB.First(&collection, "id = ?", collection.ID)
+	utils.SuccessResponse(c, http.StatusOK, "Collection updated successfully", collection)
+}
+
+func DeleteCollection(c *gin.Context) {
+	collection, ok := findOwnCollection(c)
+	if !ok {
+		return
+	}
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("collection_id = ?", collection.ID).Delete(&models.CollectionItem{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&collection).Error
+	})
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError,
+			"Failed to delete collection: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Collection deleted successfully", nil)
+}
+
+// AddCollectionRecipe appends a recipe to a collection, or inserts it at
+// position when one is given. Only recipes the owner can open may be added.
+func AddCollectionRecipe(c *gin.Context) {
+	collection, ok := findOwnCollection(c)
+	if !ok {
+		return
+	}
+
+	var input models.CollectionRecipeInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest,
+			"Invalid collection entry. recipe_id is required: "+err.Error())
+		return
+	}
+
+	var recipe models.Recipe
+	if err := db.DB.Scopes(readableRecipes(currentUser(c))).First(&recipe, "id = ?", input.RecipeID).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "Recipe not found")
+		return
+	}
+
+	var existing int64
+	db.DB.Model(&models.CollectionItem{}).
+		Where("collection_id = ? AND recipe_id = ?", collection.ID, recipe.ID).
+		Count(&existing)
+	if existing > 0 {
+		utils.ErrorResponse(c, http.StatusConflict, "This recipe is already in the collection")
+		return
+	}
+
+	item := models.CollectionItem{
+		CollectionID: collection.ID,
+		RecipeID:     recipe.ID,
+		Note:         input.Note,
+	}
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		// Purging trashed recipes can leave gaps, so append after the
+		// highest position rather than at the entry count.
+		var next int
+		if err := tx.Model(&models.CollectionItem{}).Where("collection_id = ?", collection.ID).
+			Select("COALESCE(MAX(position), -1) + 1").Scan(&next).Error; err != nil {
+			return err
+		}
+
+		item.Position = next
+		if input.Position != nil && *input.Position < next {
+			item.Position = *input.Position
+			if err := tx.Model(&models.CollectionItem{}).
+				Where("collection_id = ? AND position >= ?", collection.ID, item.Position).
+				Update("position", gorm.Expr("position + 1")).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Omit("Recipe").Create(&item).Error
+	})
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError,
+			"Failed to add recipe to collection: "+err.Error())
+		return
+	}
+
+	item.Recipe = &recipe
+	utils.SuccessResponse(c, http.StatusCreated, "Recipe added to collection 📚", item)
+}
+
+func UpdateCollectionRecipe(c *gin.Context) {
+	collection, ok := findOwnCollection(c)
+	if !ok {
+		return
+	}
+
+	item, ok := findCollectionItem(c, collection.ID)
+	if !ok {
+		return
+	}
+
+	var input models.CollectionNoteInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid collection entry: "+err.Error())
+		return
+	}
+
+	if err := db.DB.Model(&item).Update("note", input.Note).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError,
+			"Failed to update collection entry: "+err.Error())
+		return
+	}
+
+	db.DB.First(&item, "id = ?", item.ID)
+	utils.SuccessResponse(c, http.StatusOK, "Collection entry updated successfully", item)
+}
+
+func RemoveCollectionRecipe(c *gin.Context) {
+	collection, ok := findOwnCollection(c)
+	if !ok {
+		return
+	}
+
+	item, ok := findCollectionItem(c, collection.ID)
+	if !ok {
+		return
+	}
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&item).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.CollectionItem{}).
+			Where("collection_id = ? AND position > ?", collection.ID, item.Position).
+			Update("position", gorm.Expr("position - 1")).Error
+	})
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError,
+			"Failed to remove recipe from collection: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Recipe removed from collection", nil)
+}
+
+// ReorderCollection sets the order of the recipes the owner can currently
+// see. Entries whose recipe is in the trash or no longer readable keep their
+// relative order after the others.
+func ReorderCollection(c *gin.Context) {
+	collection, ok := findOwnCollection(c)
+	if !ok {
+		return
+	}
+
+	var input models.ReorderCollectionInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest,
+			"Invalid reorder data. recipe_ids must list every recipe in the new order: "+err.Error())
+		return
+	}
+
+	var items []models.CollectionItem
+	if err := db.DB.Scopes(orderedCollectionItems).
+		Preload("Recipe", readableRecipes(currentUser(c))).
+		Where("collection_id = ?", collection.ID).
+		Find(&items).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError,
+			"Failed to fetch collection recipes: "+err.Error())
+		return
+	}
+
+	known := make(map[string]bool, len(items))
+	for _, item := range items {
+		if item.Recipe != nil {
+			known[item.RecipeID] = true
+		}
+	}
+	seen := make(map[string]bool, len(input.RecipeIDs))
+	for _, id := range input.RecipeIDs {
+		if !known[id] || seen[id] {
+			utils.ErrorResponse(c, http.StatusBadRequest,
+				"recipe_ids must contain each of the collection's recipes exactly once")
+			return
+		}
+		seen[id] = true
+	}
+	if len(seen) != len(known) {
+		utils.ErrorResponse(c, http.StatusBadRequest,
+			"recipe_ids must contain each of the collection's recipes exactly once")
+		return
+	}
+
+	order := append([]string{}, input.RecipeIDs...)
+	for _, item := range items {
+		if item.Recipe == nil {
+			order = append(order, item.RecipeID)
+		}
+	}
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		for position, id := range order {
+			if err := tx.Model(&models.CollectionItem{}).
+				Where("collection_id = ? AND recipe_id = ?", collection.ID, id).
+				Update("position", position).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError,
+			"Failed to reorder collection: "+err.Error())
+		return
+	}
+
+	if err := loadCollectionItems(&collection, currentUser(c)); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError,
+			"Failed to fetch collection recipes: "+err.Error())
+		return
+	}
+	utils.SuccessResponse(c, http.StatusOK, "Collection reordered successfully", collection)
+}
+
+// GetUserCollections lists a user's collections, newest first. Owners see
+// their private collections too; everyone else only sees public ones.
+func GetUserCollections(c *gin.Context) {
+	var owner models.User
+	if err := db.DB.Select("id").First(&owner, "id = ?", c.Param("id")).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "User not found")
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "10"))
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 || perPage > 100 {
+		perPage = 10
+	}
+
+	viewer := currentUser(c)
+	query := db.DB.Model(&models.Collection{}).Where("user_id = ?", owner.ID)
+	if viewer == nil || viewer.ID != owner.ID {
+		query = query.Where("visibility = ?", models.VisibilityPublic)
+	}
+
+	var totalCount int64
+	query.Count(&totalCount)
+
+	var collections []models.Collection
+	result := query.Order("created_at DESC, id DESC").
+		Limit(perPage).
+		Offset((page - 1) * perPage).
+		Find(&collections)
+	if result.Error != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError,
+			"Failed to fetch collections: "+result.Error.Error())
+		return
+	}
+
+	if err := countCollectionRecipes(collections, viewer); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError,
+			"Failed to count collection recipes: "+err.Error())
+		return
+	}
+
+	utils.PaginatedSuccessResponse(c, http.StatusOK,
+		"Collections fetched successfully", collections, page, perPage, totalCount)
+}
+
+// findCollection loads the :id collection for reading. Another user's
+// private collection answers 404 so its existence is not revealed.
+func findCollection(c *gin.Context) (models.Collection, bool) {
+	var collection models.Collection
+	err := db.DB.First(&collection, "id = ?", c.Param("id")).Error
+	if err == nil && collection.Visibility != models.VisibilityPublic {
+		if user := currentUser(c); user == nil || user.ID != collection.UserID {
+			err = gorm.ErrRecordNotFound
+		}
+	}
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "Collection not found")
+		return collection, false
+	}
+	return collection, true
+}
+
+// findOwnCollection loads the :id collection for changes, which only its
+// owner may make.
+func findOwnCollection(c *gin.Context) (models.Collection, bool) {
+	collection, ok := findCollection(c)
+	if !ok {
+		return collection, false
+	}
+	if user := currentUser(c); user == nil || user.ID != collection.UserID {
+		utils.ErrorResponse(c, http.StatusForbidden, "You can only change your own collections")
+		return collection, false
+	}
+	return collection, true
+}
+
+func findCollectionItem(c *gin.Context, collectionID string) (models.CollectionItem, bool) {
+	var item models.CollectionItem
+	err := db.DB.First(&item, "collection_id = ? AND recipe_id = ?", collectionID, c.Param("recipeId")).Error
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "This recipe is not in the collection")
+		return item, false
+	}
+	return item, true
+}
+
+func orderedCollectionItems(tx *gorm.DB) *gorm.DB {
+	return tx.Order("position ASC, created_at ASC")
+}
+
+// loadCollectionItems fills in the collection's entries with their recipes,
+// dropping entries whose recipe viewer may not open or that is in the trash.
+func loadCollectionItems(collection *models.Collection, viewer *models.User) error {
+	var items []models.CollectionItem
+	err := db.DB.Scopes(orderedCollectionItems).
+		Preload("Recipe", readableRecipes(viewer)).
+		Where("collection_id = ?", collection.ID).
+		Find(&items).Error
+	if err != nil {
+		return err
+	}
+
+	collection.Items = make([]models.CollectionItem, 0, len(items))
+	for _, item := range items {
+		if item.Recipe != nil {
+			collection.Items = append(collection.Items, item)
+		}
+	}
+	collection.RecipeCount = len(collection.Items)
+	return nil
+}
+
+// countCollectionRecipes sets RecipeCount on each collection, counting only
+// the recipes viewer would see when opening it.
+func countCollectionRecipes(collections []models.Collection, viewer *models.User) error {
+	if len(collections) == 0 {
+		return nil
+	}
+	ids := make([]string, len(collections))
+	for i, collection := range collections {
+		ids[i] = collection.ID
+	}
+
+	var counts []struct {
+		CollectionID string
+		Count        int
+	}
+	err := db.DB.Model(&models.CollectionItem{}).
+		Select("collection_items.collection_id, COUNT(*) AS count").
+		Joins("JOIN recipes ON recipes.id = collection_items.recipe_id AND recipes.deleted_at IS NULL").
+		Scopes(readableRecipes(viewer)).
+		Where("collection_items.collection_id IN ?", ids).
+		Group("collection_items.collection_id").
+		Scan(&counts).Error
+	if err != nil {
+		return err
+	}
+
+	byID := make(map[string]int, len(counts))
+	for _, row := range counts {
+		byID[row.CollectionID] = row.Count
+	}
+	for i := range collections {
+		collections[i].RecipeCount = byID[collections[i].ID]
+	}
+	return nil
+}
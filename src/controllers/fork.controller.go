@@ -0,0 +1,197 @@
+package controllers
+
+import (
+	"log"
+	"net/http"
+
+	"recipe-api/src/db"
+	"recipe-api/src/models"
+	"recipe-api/src/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// maxAncestryDepth bounds the walk up forked_from links.
+const maxAncestryDepth = 50
+
+// ForkRecipe copies a recipe into the caller's account: content, image,
+// ingredients, steps and tags. Ratings and revisions start fresh, and the
+// copy links back to its source through forked_from. The fork keeps the
+// source's visibility so copying an unlisted recipe does not list it.
+func ForkRecipe(c *gin.Context) {
+	var source models.Recipe
+	result := db.DB.Scopes(readableRecipes(currentUser(c))).
+		Preload("IngredientItems", orderedIngredients).
+		Preload("Steps", orderedSteps).
+		Preload("Tags").
+		First(&source, "id = ?", c.Param("id"))
+	if result.Error != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "Recipe not found")
+		return
+	}
+
+	imageURL, err := utils.CopyImage(source.ImageURL)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError,
+			"Failed to copy recipe image: "+err.Error())
+		return
+	}
+
+	user := currentUser(c)
+	fork := models.Recipe{
+		Title:          source.Title,
+		Description:    source.Description,
+		ImageURL:       imageURL,
+		Ingredients:    source.Ingredients,
+		PrepTime:       source.PrepTime,
+		CookTime:       source.CookTime,
+		Servings:       source.Servings,
+		WeightedRating: utils.BayesianRating(0, 0),
+		Labels:         source.Labels,
+		Visibility:     source.Visibility,
+		UserID:         user.ID,
+		ForkedFromID:   &source.ID,
+	}
+
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("IngredientItems", "Steps", "Tags", "Ratings").Create(&fork).Error; err != nil {
+			return err
+		}
+
+		items := make([]models.RecipeIngredient, len(source.IngredientItems))
+		for i, item := range source.IngredientItems {
+			item.ID = ""
+			items[i] = item
+		}
+		if err := replaceRecipeIngredients(tx, fork.ID, items); err != nil {
+			return err
+		}
+
+		newIDs := make(map[string]string, len(items))
+		for i, item := range source.IngredientItems {
+			newIDs[item.ID] = items[i].ID
+		}
+		for _, step := range source.Steps {
+			refs := models.StringList{}
+			for _, id := range step.IngredientIDs {
+				if newID, ok := newIDs[id]; ok {
+					refs = append(refs, newID)
+				}
+			}
+			copied := models.RecipeStep{
+				RecipeID:        fork.ID,
+				Position:        step.Position,
+				Instruction:     step.Instruction,
+				DurationMinutes: step.DurationMinutes,
+				IngredientIDs:   refs,
+			}
+			if err := tx.Create(&copied).Error; err != nil {
+				return err
+			}
+		}
+
+		if len(source.Tags) > 0 {
+			if err := tx.Model(&fork).Association("Tags").Append(source.Tags); err != nil {
+				return err
+			}
+		}
+
+		if err := models.ReindexRecipe(tx, fork.ID); err != nil {
+			return err
+		}
+		return recordRevision(tx, fork.ID, user.ID, models.RevisionActionCreate, nil)
+	})
+	if err != nil {
+		if imageURL != source.ImageURL {
+			if removeErr := utils.RemoveImage(imageURL); removeErr != nil {
+				log.Printf("⚠️  Could not remove copied image %s: %v", imageURL, removeErr)
+			}
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError,
+			"Failed to fork recipe: "+err.Error())
+		return
+	}
+
+	db.DB.Preload("IngredientItems", orderedIngredients).
+		Preload("Steps", orderedSteps).
+		Preload("Tags").
+		First(&fork, "id = ?", fork.ID)
+	fork.Attribution = recipeAttribution(user, &fork)
+
+	utils.SuccessResponse(c, http.StatusCreated, "Recipe forked successfully! 🍴", fork)
+}
+
+// GetRecipeForks lists the direct forks of a recipe and its ancestry, from
+// the recipe it was forked from up to the original.
+func GetRecipeForks(c *gin.Context) {
+	user := currentUser(c)
+	var recipe models.Recipe
+	if err := db.DB.Scopes(readableRecipes(user)).First(&recipe, "id = ?", c.Param("id")).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "Recipe not found")
+		return
+	}
+
+	var forks []models.Recipe
+	result := db.DB.Scopes(listedRecipes(user)).
+		Where("forked_from_id = ?", recipe.ID).
+		Order("created_at DESC").
+		Find(&forks)
+	if result.Error != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError,
+			"Failed to fetch forks: "+result.Error.Error())
+		return
+	}
+
+	ancestry := []models.RecipeAttribution{}
+	seen := map[string]bool{recipe.ID: true}
+	parentID := recipe.ForkedFromID
+	for parentID != nil && !seen[*parentID] && len(ancestry) < maxAncestryDepth {
+		seen[*parentID] = true
+		var parent models.Recipe
+		if err := db.DB.First(&parent, "id = ?", *parentID).Error; err != nil {
+			ancestry = append(ancestry, models.RecipeAttribution{RecipeID: *parentID})
+			break
+		}
+		ancestry = append(ancestry, *attributionFor(user, &parent))
+		parentID = parent.ForkedFromID
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Recipe lineage fetched successfully", gin.H{
+		"recipe_id":  recipe.ID,
+		"ancestry":   ancestry,
+		"fork_count": len(forks),
+		"forks":      forks,
+	})
+}
+
+// recipeAttribution describes the recipe this one was forked from, or nil
+// for originals.
+func recipeAttribution(user *models.User, recipe *models.Recipe) *models.RecipeAttribution {
+	if recipe.ForkedFromID == nil {
+		return nil
+	}
+	var parent models.Recipe
+	if err := db.DB.First(&parent, "id = ?", *recipe.ForkedFromID).Error; err != nil {
+		return &models.RecipeAttribution{RecipeID: *recipe.ForkedFromID}
+	}
+	return attributionFor(user, &parent)
+}
+
+// attributionFor credits recipe as seen by user. Recipes the user may not
+// open are reduced to their ID.
+func attributionFor(user *models.User, recipe *models.Recipe) *models.RecipeAttribution {
+	if !canReadRecipe(user, recipe) {
+		return &models.RecipeAttribution{RecipeID: recipe.ID}
+	}
+	attribution := &models.RecipeAttribution{
+		RecipeID: recipe.ID,
+		Title:    recipe.Title,
+		AuthorID: recipe.UserID,
+	}
+	var author models.User
+	if err := db.DB.Select("id", "username").First(&author, "id = ?", recipe.UserID).Error; err == nil {
+		attribution.AuthorUsername = author.Username
+	}
+	return attribution
+}
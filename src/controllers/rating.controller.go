@@ -1,44 +1,74 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 
 	"recipe-api/src/db"
 	"recipe-api/src/models"
 	"recipe-api/src/utils"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 func AddRating(c *gin.Context) {
 	recipeID := c.Param("id")
 
 	var recipe models.Recipe
-	if err := db.DB.First(&recipe, "id = ?", recipeID).Error; err != nil {
+	if err := db.DB.Scopes(readableRecipes(currentUser(c))).First(&recipe, "id = ?", recipeID).Error; err != nil {
 		utils.ErrorResponse(c, http.StatusNotFound, "Recipe not found")
 		return
 	}
 
-	var rating models.Rating
-	if err := c.ShouldBindJSON(&rating); err != nil {
+	var input models.RatingInput
+	if err := c.ShouldBindJSON(&input); err != nil {
 		utils.ErrorResponse(c, http.StatusBadRequest,
-			"Invalid rating data. Score must be 1-5 and user_name is required: "+err.Error())
+			"Invalid rating data. Score must be 1-5: "+err.Error())
 		return
 	}
 
-	rating.RecipeID = recipeID
+	user := currentUser(c)
+	rating := models.Rating{
+		RecipeID: recipeID,
+		UserID:   user.ID,
+		Score:    input.Score,
+		Comment:  input.Comment,
+	}
 
-	result := db.DB.Create(&rating)
-	if result.Error != nil {
-		utils.ErrorResponse(c, http.StatusInternalServerError,
-			"Failed to add rating: "+result.Error.Error())
+	var existing int64
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Rating{}).
+			Where("recipe_id = ? AND user_id = ?", recipeID, user.ID).
+			Count(&existing).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "recipe_id"}, {Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"score", "comment", "updated_at"}),
+		}).Create(&rating).Error; err != nil {
+			return err
+		}
+
+		if err := tx.First(&rating, "recipe_id = ? AND user_id = ?", recipeID, user.ID).Error; err != nil {
+			return err
+		}
+
+		return updateRatingStats(tx, &recipe)
+	})
+	if err != nil {
+		respondRatingError(c, "Failed to save rating: ", err)
 		return
 	}
 
-	updateAverageRating(recipeID)
-	db.DB.First(&recipe, "id = ?", recipeID)
-
-	utils.SuccessResponse(c, http.StatusCreated, "Rating added successfully! ⭐", gin.H{
+	status, message := http.StatusCreated, "Rating added successfully! ⭐"
+	if existing > 0 {
+		status, message = http.StatusOK, "Rating updated successfully! ⭐"
+	}
+	utils.SuccessResponse(c, status, message, gin.H{
 		"rating": rating,
 		"recipe": recipe,
 	})
@@ -48,15 +78,50 @@ func GetRatings(c *gin.Context) {
 	recipeID := c.Param("id")
 
 	var recipe models.Recipe
-	if err := db.DB.First(&recipe, "id = ?", recipeID).Error; err != nil {
+	if err := db.DB.Scopes(readableRecipes(currentUser(c))).First(&recipe, "id = ?", recipeID).Error; err != nil {
 		utils.ErrorResponse(c, http.StatusNotFound, "Recipe not found")
 		return
 	}
 
+	query := db.DB.Preload("User").
+		Where("recipe_id = ? AND hidden = ?", recipeID, false)
+
+	summary := func(ratings []models.Rating) gin.H {
+		return gin.H{
+			"recipe_id":        recipeID,
+			"average_rating":   recipe.AverageRating,
+			"weighted_rating":  recipe.WeightedRating,
+			"rating_count":     recipe.RatingCount,
+			"rating_histogram": recipe.RatingHistogram,
+			"count":            len(ratings),
+			"ratings":          ratings,
+		}
+	}
+
+	if cursor, ok := c.GetQuery("cursor"); ok {
+		perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "20"))
+		if perPage < 1 || perPage > 100 {
+			perPage = 20
+		}
+
+		page, err := ratingKeyset.Paginate(query, cursor, perPage)
+		if errors.Is(err, utils.ErrInvalidCursor) {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Invalid cursor. Start again without ?cursor= or use one from the previous response")
+			return
+		}
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusInternalServerError,
+				"Failed to fetch ratings: "+err.Error())
+			return
+		}
+
+		utils.CursorPaginatedSuccessResponse(c, http.StatusOK, "Ratings fetched successfully",
+			summary(page.Items), perPage, page.NextCursor, page.PrevCursor)
+		return
+	}
+
 	var ratings []models.Rating
-	result := db.DB.Where("recipe_id = ?", recipeID).
-		Order("created_at DESC").
-		Find(&ratings)
+	result := query.Order("created_at DESC").Find(&ratings)
 
 	if result.Error != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError,
@@ -64,25 +129,218 @@ func GetRatings(c *gin.Context) {
 		return
 	}
 
-	utils.SuccessResponse(c, http.StatusOK, "Ratings fetched successfully", gin.H{
-		"recipe_id":      recipeID,
-		"average_rating": recipe.AverageRating,
-		"count":          len(ratings),
-		"ratings":        ratings,
+	utils.SuccessResponse(c, http.StatusOK, "Ratings fetched successfully", summary(ratings))
+}
+
+// ratingKeyset pages ratings newest first.
+var ratingKeyset = utils.KeysetSort[models.Rating]{
+	Name:     "newest",
+	Column:   "ratings.created_at",
+	IDColumn: "ratings.id",
+	Desc:     true,
+	Key:      func(r *models.Rating) interface{} { return r.CreatedAt },
+	ID:       func(r *models.Rating) string { return r.ID },
+}
+
+func UpdateRating(c *gin.Context) {
+	rating, ok := findRecipeRating(c)
+	if !ok {
+		return
+	}
+
+	if user := currentUser(c); user == nil || rating.UserID != user.ID {
+		utils.ErrorResponse(c, http.StatusForbidden, "You can only edit your own ratings")
+		return
+	}
+
+	var input models.RatingInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest,
+			"Invalid rating data. Score must be 1-5: "+err.Error())
+		return
+	}
+
+	var recipe models.Recipe
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&rating).Updates(map[string]interface{}{
+			"score":   input.Score,
+			"comment": input.Comment,
+		}).Error; err != nil {
+			return err
+		}
+		recipe.ID = rating.RecipeID
+		return updateRatingStats(tx, &recipe)
+	})
+	if err != nil {
+		respondRatingError(c, "Failed to update rating: ", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Rating updated successfully! ⭐", gin.H{
+		"rating": rating,
+		"recipe": recipe,
+	})
+}
+
+func DeleteRating(c *gin.Context) {
+	rating, ok := findRecipeRating(c)
+	if !ok {
+		return
+	}
+
+	user := currentUser(c)
+	if user == nil || (rating.UserID != user.ID && !user.Can(models.PermRatingsDeleteAny)) {
+		utils.ErrorResponse(c, http.StatusForbidden, "You can only delete your own ratings")
+		return
+	}
+
+	recipe, err := deleteRatingAndRefresh(&rating)
+	if err != nil {
+		respondRatingError(c, "Failed to delete rating: ", err)
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Rating deleted successfully", gin.H{
+		"recipe": recipe,
+	})
+}
+
+// findRecipeRating loads a rating of a recipe the caller can open. Ratings of
+// trashed or hidden recipes look like they do not exist.
+func findRecipeRating(c *gin.Context) (models.Rating, bool) {
+	var rating models.Rating
+	readable := db.DB.Model(&models.Recipe{}).Select("id").Scopes(readableRecipes(currentUser(c)))
+	err := db.DB.Where("recipe_id IN (?)", readable).
+		First(&rating, "id = ? AND recipe_id = ?", c.Param("ratingId"), c.Param("id")).Error
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "Rating not found")
+		return rating, false
+	}
+	return rating, true
+}
+
+func deleteRatingAndRefresh(rating *models.Rating) (models.Recipe, error) {
+	recipe := models.Recipe{ID: rating.RecipeID}
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(rating).Error; err != nil {
+			return err
+		}
+		return updateRatingStats(tx, &recipe)
 	})
+	return recipe, err
+}
+
+// errRecipeNotFound is returned by updateRatingStats when the rating's recipe
+// no longer exists at all.
+var errRecipeNotFound = errors.New("recipe not found")
+
+// respondRatingError answers 404 for errRecipeNotFound and 500 with message
+// for anything else.
+func respondRatingError(c *gin.Context, message string, err error) {
+	if errors.Is(err, errRecipeNotFound) {
+		utils.ErrorResponse(c, http.StatusNotFound, "Recipe not found")
+		return
+	}
+	utils.ErrorResponse(c, http.StatusInternalServerError, message+err.Error())
 }
 
-func updateAverageRating(recipeID string) {
-	var avgResult struct {
+// updateRatingStats recomputes the average, Bayesian weighted score, count and
+// score histogram of the recipe's visible ratings and stores them on the
+// recipe. It must run inside the same transaction as the rating change so
+// concurrent writers cannot leave a stale aggregate behind. Trashed recipes
+// are updated too, so moderation keeps their stats right for a restore. On
+// success recipe is reloaded.
+func updateRatingStats(tx *gorm.DB, recipe *models.Recipe) error {
+	var stats struct {
 		Average float64
+		Count   int
+		Score1  int
+		Score2  int
+		Score3  int
+		Score4  int
+		Score5  int
+	}
+
+	err := tx.Model(&models.Rating{}).
+		Select(`COALESCE(AVG(score), 0) AS average, COUNT(*) AS count,
+			COALESCE(SUM(CASE WHEN score = 1 THEN 1 ELSE 0 END), 0) AS score1,
+			COALESCE(SUM(CASE WHEN score = 2 THEN 1 ELSE 0 END), 0) AS score2,
+			COALESCE(SUM(CASE WHEN score = 3 THEN 1 ELSE 0 END), 0) AS score3,
+			COALESCE(SUM(CASE WHEN score = 4 THEN 1 ELSE 0 END), 0) AS score4,
+			COALESCE(SUM(CASE WHEN score = 5 THEN 1 ELSE 0 END), 0) AS score5`).
+		Where("recipe_id = ? AND hidden = ?", recipe.ID, false).
+		Scan(&stats).Error
+	if err != nil {
+		return err
+	}
+
+	histogram := models.RatingHistogram{stats.Score1, stats.Score2, stats.Score3, stats.Score4, stats.Score5}
+	result := tx.Unscoped().Model(&models.Recipe{}).
+		Where("id = ?", recipe.ID).
+		Updates(map[string]interface{}{
+			"average_rating":   stats.Average,
+			"weighted_rating":  utils.BayesianRating(stats.Average, stats.Count),
+			"rating_count":     stats.Count,
+			"rating_histogram": histogram,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errRecipeNotFound
+	}
+
+	return tx.Unscoped().First(recipe, "id = ?", recipe.ID).Error
+}
+
+func HideRating(c *gin.Context) {
+	setRatingHidden(c, true)
+}
+
+func UnhideRating(c *gin.Context) {
+	setRatingHidden(c, false)
+}
+
+func setRatingHidden(c *gin.Context, hidden bool) {
+	id := c.Param("ratingId")
+	var rating models.Rating
+
+	if err := db.DB.First(&rating, "id = ?", id).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "Rating not found")
+		return
+	}
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&rating).Update("hidden", hidden).Error; err != nil {
+			return err
+		}
+		return updateRatingStats(tx, &models.Recipe{ID: rating.RecipeID})
+	})
+	if err != nil {
+		respondRatingError(c, "Failed to update rating visibility: ", err)
+		return
+	}
+
+	message := "Rating hidden"
+	if !hidden {
+		message = "Rating restored"
+	}
+	utils.SuccessResponse(c, http.StatusOK, message, rating)
+}
+
+func DeleteAnyRating(c *gin.Context) {
+	id := c.Param("ratingId")
+	var rating models.Rating
+
+	if err := db.DB.First(&rating, "id = ?", id).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "Rating not found")
+		return
 	}
 
-	db.DB.Model(&models.Rating{}).
-		Select("COALESCE(AVG(score), 0) as average").
-		Where("recipe_id = ?", recipeID).
-		Scan(&avgResult)
+	if _, err := deleteRatingAndRefresh(&rating); err != nil {
+		respondRatingError(c, "Failed to delete rating: ", err)
+		return
+	}
 
-	db.DB.Model(&models.Recipe{}).
-		Where("id = ?", recipeID).
-		Update("average_rating", avgResult.Average)
+	utils.SuccessResponse(c, http.StatusOK, "Rating deleted successfully", nil)
 }
@@ -0,0 +1,106 @@
+package controllers_test
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"recipe-api/src/db"
+	"recipe-api/src/models"
+	"recipe-api/src/routes"
+	"recipe-api/src/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// newTestServer connects db.DB to a fresh SQLite file and returns the API
+// router.
+func newTestServer(t *testing.T) *gin.Engine {
+	t.Helper()
+	t.Setenv("DB_PATH", filepath.Join(t.TempDir(), "test.db"))
+	t.Setenv("JWT_SECRET", "test-secret")
+	gin.SetMode(gin.TestMode)
+
+	db.ConnectDatabase()
+	t.Cleanup(func() {
+		if sqlDB, err := db.DB.DB(); err == nil {
+			sqlDB.Close()
+		}
+	})
+
+	router := gin.New()
+	routes.SetupRoutes(router)
+	return router
+}
+
+// newTestUser stores a user and returns it with a bearer token.
+func newTestUser(t *testing.T, username string) (models.User, string) {
+	t.Helper()
+	user := models.User{Username: username, Email: username + "@example.com"}
+	if err := db.DB.Create(&user).Error; err != nil {
+		t.Fatalf("create user %s: %v", username, err)
+	}
+	token, _, err := utils.GenerateToken(user.ID, user.Username)
+	if err != nil {
+		t.Fatalf("token for %s: %v", username, err)
+	}
+	return user, token
+}
+
+func TestConcurrentRatingsKeepStatsConsistent(t *testing.T) {
+	router := newTestServer(t)
+
+	owner, _ := newTestUser(t, "owner")
+	recipe := models.Recipe{Title: "Soup", UserID: owner.ID, Ingredients: "[]"}
+	if err := db.DB.Create(&recipe).Error; err != nil {
+		t.Fatalf("create recipe: %v", err)
+	}
+
+	const raters = 20
+	tokens := make([]string, raters)
+	for i := range tokens {
+		_, tokens[i] = newTestUser(t, fmt.Sprintf("rater%d", i))
+	}
+
+	var wg sync.WaitGroup
+	statuses := make([]int, raters)
+	bodies := make([]string, raters)
+	for i, token := range tokens {
+		wg.Add(1)
+		go func(i int, token string) {
+			defer wg.Done()
+			body := fmt.Sprintf(`{"score":%d}`, i%5+1)
+			req := httptest.NewRequest(http.MethodPost, "/api/recipes/"+recipe.ID+"/ratings", bytes.NewBufferString(body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Authorization", "Bearer "+token)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			statuses[i], bodies[i] = w.Code, w.Body.String()
+		}(i, token)
+	}
+	wg.Wait()
+
+	for i, status := range statuses {
+		if status != http.StatusCreated {
+			t.Errorf("rater %d: status %d, body %s", i, status, bodies[i])
+		}
+	}
+
+	var stored models.Recipe
+	if err := db.DB.First(&stored, "id = ?", recipe.ID).Error; err != nil {
+		t.Fatalf("reload recipe: %v", err)
+	}
+	if stored.RatingCount != raters {
+		t.Errorf("rating_count = %d, want %d", stored.RatingCount, raters)
+	}
+	if stored.AverageRating != 3 {
+		t.Errorf("average_rating = %v, want 3", stored.AverageRating)
+	}
+	if want := (models.RatingHistogram{4, 4, 4, 4, 4}); stored.RatingHistogram != want {
+		t.Errorf("rating_histogram = %v, want %v", stored.RatingHistogram, want)
+	}
+}
@@ -2,26 +2,125 @@ package controllers
 
 import (
 	"encoding/json"
+	"errors"
+	"math"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"recipe-api/src/db"
 	"recipe-api/src/models"
 	"recipe-api/src/utils"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
+// recipeSortOrders whitelists the ?sort= values of the recipe list.
+var recipeSortOrders = map[string]string{
+	"newest":     "created_at DESC",
+	"top":        "weighted_rating DESC, rating_count DESC, created_at DESC",
+	"rating":     "average_rating DESC, rating_count DESC, created_at DESC",
+	"total_time": "prep_time + cook_time ASC, created_at DESC",
+	"title":      "title COLLATE NOCASE ASC, created_at DESC",
+}
+
+// recipeKeysets holds the cursor pagination variant of each
+// recipeSortOrders entry. Each sort pages on its primary key only, with the
+// recipe ID as tie-breaker; the secondary keys of the offset orders
+// (rating_count, created_at) are not part of the cursor, so ties are
+// ordered by ID instead.
+var recipeKeysets = map[string]utils.KeysetSort[models.Recipe]{
+	"newest": recipeKeyset("newest", "recipes.created_at", true,
+		func(r *models.Recipe) interface{} { return r.CreatedAt }),
+	"top": recipeKeyset("top", "recipes.weighted_rating", true,
+		func(r *models.Recipe) interface{} { return r.WeightedRating }),
+	"rating": recipeKeyset("rating", "recipes.average_rating", true,
+		func(r *models.Recipe) interface{} { return r.AverageRating }),
+	"total_time": recipeKeyset("total_time", "recipes.prep_time + recipes.cook_time", false,
+		func(r *models.Recipe) interface{} { return r.PrepTime + r.CookTime }),
+	"title": recipeKeyset("title", "recipes.title COLLATE NOCASE", false,
+		func(r *models.Recipe) interface{} { return r.Title }),
+}
+
+func recipeKeyset(name, column string, desc bool, key func(*models.Recipe) interface{}) utils.KeysetSort[models.Recipe] {
+	return utils.KeysetSort[models.Recipe]{
+		Name:     name,
+		Column:   column,
+		IDColumn: "recipes.id",
+		Desc:     desc,
+		Key:      key,
+		ID:       func(r *models.Recipe) string { return r.ID },
+	}
+}
+
+// respondRecipeCursorPage writes one keyset page of query in the cursor
+// envelope. sort must already be validated against recipeSortOrders.
+func respondRecipeCursorPage(c *gin.Context, query *gorm.DB, sort, cursor string, perPage int) {
+	page, err := recipeKeysets[sort].Paginate(query.Preload("Tags"), cursor, perPage)
+	if errors.Is(err, utils.ErrInvalidCursor) {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid cursor. Start again without ?cursor= or use one from the previous response")
+		return
+	}
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError,
+			"Failed to fetch recipes: "+err.Error())
+		return
+	}
+
+	utils.CursorPaginatedSuccessResponse(c, http.StatusOK,
+		"Recipes fetched successfully", page.Items, perPage, page.NextCursor, page.PrevCursor)
+}
+
+type recipeRangeFilter struct {
+	param      string
+	expression string
+	operator   string
+	max        float64
+}
+
+// recipeRangeFilters whitelists the numeric list filters. Only these
+// parameters reach SQL, and always as bound values against fixed columns.
+var recipeRangeFilters = []recipeRangeFilter{
+	{"min_prep_time", "recipes.prep_time", ">=", 0},
+	{"max_prep_time", "recipes.prep_time", "<=", 0},
+	{"min_cook_time", "recipes.cook_time", ">=", 0},
+	{"max_cook_time", "recipes.cook_time", "<=", 0},
+	{"min_total_time", "recipes.prep_time + recipes.cook_time", ">=", 0},
+	{"max_total_time", "recipes.prep_time + recipes.cook_time", "<=", 0},
+	{"min_servings", "recipes.servings", ">=", 0},
+	{"max_servings", "recipes.servings", "<=", 0},
+	{"min_rating", "recipes.average_rating", ">=", 5},
+}
+
 func CreateRecipe(c *gin.Context) {
 	title := c.PostForm("title")
 	description := c.PostForm("description")
 	ingredients := c.PostForm("ingredients")
-	userID := c.PostForm("user_id")
 	prepTime, _ := strconv.Atoi(c.DefaultPostForm("prep_time", "0"))
 	cookTime, _ := strconv.Atoi(c.DefaultPostForm("cook_time", "0"))
 	servings, _ := strconv.Atoi(c.DefaultPostForm("servings", "1"))
 
+	publishAt, err := parsePublishAt(c.PostForm("publish_at"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	visibility := c.PostForm("visibility")
+	if visibility == "" {
+		visibility = models.VisibilityPublic
+		if publishAt != nil {
+			visibility = models.VisibilityDraft
+		}
+	}
+	if err := checkPublishing(visibility, publishAt); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
 	if title == "" {
 		utils.ErrorResponse(c, http.StatusBadRequest, "Title is required")
 		return
@@ -31,10 +130,9 @@ func CreateRecipe(c *gin.Context) {
 		return
 	}
 
-	var ingredientList []string
-	if err := json.Unmarshal([]byte(ingredients), &ingredientList); err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest,
-			"Ingredients must be a valid JSON array. Example: [\"tomato\",\"onion\"]")
+	ingredientItems, err := parseIngredients(ingredients)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
 		return
 	}
 
@@ -50,20 +148,30 @@ func CreateRecipe(c *gin.Context) {
 	}
 
 	recipe := models.Recipe{
-		Title:       title,
-		Description: description,
-		Ingredients: ingredients,
-		ImageURL:    imageURL,
-		PrepTime:    prepTime,
-		CookTime:    cookTime,
-		Servings:    servings,
-		UserID:      userID,
+		Title:           title,
+		Description:     description,
+		Ingredients:     ingredientsJSON(ingredientItems),
+		IngredientItems: ingredientItems,
+		Labels:          dietaryLabels(ingredientItems),
+		ImageURL:        imageURL,
+		PrepTime:        prepTime,
+		CookTime:        cookTime,
+		Servings:        servings,
+		WeightedRating:  utils.BayesianRating(0, 0),
+		Visibility:      visibility,
+		PublishAt:       publishAt,
+		UserID:          currentUser(c).ID,
 	}
 
-	result := db.DB.Create(&recipe)
-	if result.Error != nil {
+	err = db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&recipe).Error; err != nil {
+			return err
+		}
+		return recordRevision(tx, recipe.ID, recipe.UserID, models.RevisionActionCreate, nil)
+	})
+	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError,
-			"Failed to create recipe: "+result.Error.Error())
+			"Failed to create recipe: "+err.Error())
 		return
 	}
 
@@ -84,11 +192,30 @@ func GetAllRecipes(c *gin.Context) {
 		perPage = 10
 	}
 
+	sort := c.DefaultQuery("sort", "newest")
+	order, ok := recipeSortOrders[sort]
+	if !ok {
+		utils.ErrorResponse(c, http.StatusBadRequest,
+			"Invalid sort. Use one of: newest, rating, top, total_time, title")
+		return
+	}
+
 	offset := (page - 1) * perPage
 
-	db.DB.Model(&models.Recipe{}).Count(&totalCount)
+	query, ok := filterRecipes(c, db.DB.Model(&models.Recipe{}))
+	if !ok {
+		return
+	}
+
+	if cursor, ok := c.GetQuery("cursor"); ok {
+		respondRecipeCursorPage(c, query, sort, cursor, perPage)
+		return
+	}
+
+	query.Count(&totalCount)
 
-	result := db.DB.Order("created_at DESC").
+	result := query.Preload("Tags").
+		Order(order).
 		Limit(perPage).
 		Offset(offset).
 		Find(&recipes)
@@ -103,86 +230,578 @@ func GetAllRecipes(c *gin.Context) {
 		"Recipes fetched successfully", recipes, page, perPage, totalCount)
 }
 
+// filterRecipes applies the list filters from the query string. Tag filters
+// use AND semantics: ?tag=vegan&cuisine=italian only returns recipes that
+// carry both tags. ?tag= matches any tag type; the typed parameters only
+// match tags of that type. ?label= requires computed dietary labels and
+// ?exclude_allergens= drops recipes containing any listed allergen. Each
+// parameter may be repeated or comma-separated. ?author= takes a username or
+// user ID, and the range filters in recipeRangeFilters take non-negative
+// numbers. Invalid values get a 400 response and ok is false. Only public
+// recipes and the caller's own are listed.
+func filterRecipes(c *gin.Context, query *gorm.DB) (*gorm.DB, bool) {
+	query = query.Scopes(listedRecipes(currentUser(c)))
+
+	tagFilter := func(tagType string, values []string) {
+		for _, value := range values {
+			for _, name := range strings.Split(value, ",") {
+				slug := utils.Slugify(name)
+				if slug == "" {
+					continue
+				}
+				sub := db.DB.Table("recipe_tags").
+					Select("recipe_tags.recipe_id").
+					Joins("JOIN tags ON tags.id = recipe_tags.tag_id").
+					Where("tags.slug = ?", slug)
+				if tagType != "" {
+					sub = sub.Where("tags.type = ?", tagType)
+				}
+				query = query.Where("recipes.id IN (?)", sub)
+			}
+		}
+	}
+
+	tagFilter("", c.QueryArray("tag"))
+	for _, tagType := range models.TagTypes {
+		if tagType != models.TagTypeGeneral {
+			tagFilter(tagType, c.QueryArray(tagType))
+		}
+	}
+
+	const hasLabel = "EXISTS (SELECT 1 FROM json_each(recipes.labels) WHERE json_each.value = ?)"
+	for _, label := range splitQueryValues(c, "label") {
+		query = query.Where(hasLabel, label)
+	}
+	for _, allergen := range splitQueryValues(c, "exclude_allergens") {
+		allergen = strings.TrimPrefix(allergen, utils.AllergenLabelPrefix)
+		if !utils.IsKnownAllergen(allergen) {
+			utils.ErrorResponse(c, http.StatusBadRequest,
+				"Unknown allergen in exclude_allergens: "+allergen+". Use one of: "+strings.Join(utils.KnownAllergens(), ", "))
+			return query, false
+		}
+		query = query.Where("NOT "+hasLabel, utils.AllergenLabelPrefix+allergen)
+	}
+
+	if author := strings.TrimSpace(c.Query("author")); author != "" {
+		authors := db.DB.Model(&models.User{}).Select("id").Where("username = ? OR id = ?", author, author)
+		query = query.Where("recipes.user_id IN (?)", authors)
+	}
+
+	for _, filter := range recipeRangeFilters {
+		raw := c.Query(filter.param)
+		if raw == "" {
+			continue
+		}
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil || math.IsNaN(value) || math.IsInf(value, 0) || value < 0 || (filter.max > 0 && value > filter.max) {
+			message := filter.param + " must be a non-negative number"
+			if filter.max > 0 {
+				message = filter.param + " must be a number between 0 and " + strconv.FormatFloat(filter.max, 'f', -1, 64)
+			}
+			utils.ErrorResponse(c, http.StatusBadRequest, message)
+			return query, false
+		}
+		query = query.Where(filter.expression+" "+filter.operator+" ?", value)
+	}
+
+	return query, true
+}
+
+// splitQueryValues collects a repeatable, comma-separated query parameter as
+// trimmed, lower-cased values.
+func splitQueryValues(c *gin.Context, key string) []string {
+	var values []string
+	for _, raw := range c.QueryArray(key) {
+		for _, value := range strings.Split(raw, ",") {
+			if value = strings.ToLower(strings.TrimSpace(value)); value != "" {
+				values = append(values, value)
+			}
+		}
+	}
+	return values
+}
+
 func GetRecipeByID(c *gin.Context) {
 	id := c.Param("id")
 	var recipe models.Recipe
 
-	result := db.DB.Preload("Ratings").First(&recipe, "id = ?", id)
+	result := db.DB.Scopes(readableRecipes(currentUser(c))).
+		Preload("IngredientItems", orderedIngredients).
+		Preload("Steps", orderedSteps).
+		Preload("Tags").
+		Preload("Ratings", "hidden = ?", false).
+		First(&recipe, "id = ?", id)
 	if result.Error != nil {
 		utils.ErrorResponse(c, http.StatusNotFound, "Recipe not found")
 		return
 	}
 
+	nutrition := recipeNutrition(&recipe)
+	recipe.Nutrition = &nutrition
+	recipe.Attribution = recipeAttribution(currentUser(c), &recipe)
+
 	utils.SuccessResponse(c, http.StatusOK, "Recipe fetched successfully", recipe)
 }
 
+func GetRecipeNutrition(c *gin.Context) {
+	id := c.Param("id")
+	var recipe models.Recipe
+
+	result := db.DB.Scopes(readableRecipes(currentUser(c))).
+		Preload("IngredientItems", orderedIngredients).
+		First(&recipe, "id = ?", id)
+	if result.Error != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "Recipe not found")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Nutrition facts calculated successfully", gin.H{
+		"recipe_id": recipe.ID,
+		"nutrition": recipeNutrition(&recipe),
+	})
+}
+
+// recipeNutrition estimates nutrients from the recipe's loaded ingredient rows.
+func recipeNutrition(recipe *models.Recipe) utils.NutritionReport {
+	lines := make([]utils.NutritionLine, len(recipe.IngredientItems))
+	for i, item := range recipe.IngredientItems {
+		lines[i] = utils.NutritionLine{
+			Name:        item.Name,
+			Quantity:    item.Quantity,
+			QuantityMax: item.QuantityMax,
+			Unit:        item.Unit,
+		}
+	}
+	return utils.CalculateNutrition(lines, recipe.Servings)
+}
+
+func GetScaledRecipe(c *gin.Context) {
+	id := c.Param("id")
+	var recipe models.Recipe
+
+	result := db.DB.Scopes(readableRecipes(currentUser(c))).
+		Preload("IngredientItems", orderedIngredients).
+		Preload("Steps", orderedSteps).
+		First(&recipe, "id = ?", id)
+	if result.Error != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "Recipe not found")
+		return
+	}
+
+	originalServings := recipe.Servings
+	if originalServings < 1 {
+		originalServings = 1
+	}
+
+	servings := originalServings
+	if raw := c.Query("servings"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 1 || parsed > 1000 {
+			utils.ErrorResponse(c, http.StatusBadRequest, "servings must be a whole number between 1 and 1000")
+			return
+		}
+		servings = parsed
+	}
+
+	system := strings.ToLower(c.Query("system"))
+	if system != utils.SystemOriginal && system != utils.SystemMetric && system != utils.SystemUS {
+		utils.ErrorResponse(c, http.StatusBadRequest, "system must be one of: metric, us")
+		return
+	}
+
+	factor := float64(servings) / float64(originalServings)
+	for i := range recipe.IngredientItems {
+		scaleIngredient(&recipe.IngredientItems[i], factor, system)
+	}
+	recipe.Ingredients = ingredientsJSON(recipe.IngredientItems)
+	recipe.Servings = servings
+
+	utils.SuccessResponse(c, http.StatusOK, "Scaled recipe fetched successfully", gin.H{
+		"original_servings": originalServings,
+		"servings":          servings,
+		"scale_factor":      factor,
+		"system":            system,
+		"recipe":            recipe,
+	})
+}
+
+// scaleIngredient multiplies an ingredient's quantities by factor and converts
+// them into the requested measurement system. The row is never saved.
+func scaleIngredient(item *models.RecipeIngredient, factor float64, system string) {
+	if item.Quantity == nil {
+		return
+	}
+
+	quantity := *item.Quantity * factor
+	multiplier, unit := utils.ConvertUnit(quantity, item.Unit, item.Name, system)
+	quantity = utils.RoundQuantity(quantity*multiplier, unit)
+	item.Quantity = &quantity
+
+	if item.QuantityMax != nil {
+		upper := utils.RoundQuantity(*item.QuantityMax*factor*multiplier, unit)
+		item.QuantityMax = &upper
+	}
+	item.Unit = unit
+}
+
+// SearchByIngredients matches recipes whose ingredients contain any of the
+// requested terms. Terms are stemmed, mapped through the synonym dictionary
+// ("scallion" → "green onion") and typo-corrected against the known
+// ingredient vocabulary before matching.
 func SearchByIngredients(c *gin.Context) {
 	ingredientsParam := c.Query("ingredients")
-	if ingredientsParam == "" {
+	resolved := resolveIngredientTerms(ingredientsParam)
+	if len(resolved) == 0 {
 		utils.ErrorResponse(c, http.StatusBadRequest,
 			"Please provide ingredients to search. Example: ?ingredients=tomato,onion")
 		return
 	}
 
-	searchTerms := strings.Split(ingredientsParam, ",")
+	terms := make([]string, len(resolved))
+	for i, r := range resolved {
+		terms[i] = r.Term
+	}
+	terms = uniqueStrings(terms)
+
+	if c.Query("mode") == "pantry" {
+		pantrySearch(c, ingredientsParam, resolved, terms)
+		return
+	}
 
 	var recipes []models.Recipe
-	query := db.DB
+	query, ok := filterRecipes(c, db.DB.Where("recipes.id IN (?)", recipesWithIngredients(terms)))
+	if !ok {
+		return
+	}
+
+	result := query.Find(&recipes)
+	if result.Error != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError,
+			"Search failed: "+result.Error.Error())
+		return
+	}
+
+	ids := make([]string, len(recipes))
+	for i, recipe := range recipes {
+		ids[i] = recipe.ID
+	}
+	var names []string
+	db.DB.Model(&models.RecipeIngredient{}).Where("recipe_id IN ?", ids).Distinct().Pluck("search_name", &names)
+
+	matchedTerms := []string{}
+	for _, term := range terms {
+		for _, name := range names {
+			if ingredientHasTerm(name, term) {
+				matchedTerms = append(matchedTerms, term)
+				break
+			}
+		}
+	}
+
+	utils.SuccessResponse(c, http.StatusOK,
+		"Search results fetched successfully", gin.H{
+			"query":         ingredientsParam,
+			"terms":         resolved,
+			"matched_terms": matchedTerms,
+			"count":         len(recipes),
+			"recipes":       recipes,
+		})
+}
+
+// ingredientSearchTerm records how one requested ingredient was interpreted.
+type ingredientSearchTerm struct {
+	Query     string `json:"query"`
+	Term      string `json:"term"`
+	Corrected bool   `json:"corrected"`
+}
+
+// vocabularyTTL is how long the typo-correction vocabulary is reused before
+// it is rebuilt, so new public ingredients show up within a few minutes.
+const vocabularyTTL = 5 * time.Minute
+
+var vocabularyCache struct {
+	sync.Mutex
+	words    map[string]bool
+	loadedAt time.Time
+}
+
+// searchVocabulary returns the words of every ingredient in a public recipe.
+// Drafts, private and trashed recipes stay out so corrections cannot reveal
+// their ingredients.
+func searchVocabulary() map[string]bool {
+	vocabularyCache.Lock()
+	defer vocabularyCache.Unlock()
 
-	for i, term := range searchTerms {
-		term = strings.TrimSpace(strings.ToLower(term))
+	if vocabularyCache.words != nil && time.Since(vocabularyCache.loadedAt) < vocabularyTTL {
+		return vocabularyCache.words
+	}
+
+	var names []string
+	if err := db.DB.Model(&models.RecipeIngredient{}).
+		Joins("JOIN recipes ON recipes.id = recipe_ingredients.recipe_id").
+		Where("recipes.visibility = ? AND recipes.deleted_at IS NULL", models.VisibilityPublic).
+		Distinct().Pluck("recipe_ingredients.search_name", &names).Error; err != nil {
+		return utils.IngredientVocabulary(nil)
+	}
+	vocabularyCache.words = utils.IngredientVocabulary(names)
+	vocabularyCache.loadedAt = time.Now()
+	return vocabularyCache.words
+}
+
+// resolveIngredientTerms normalizes each comma-separated query and corrects
+// typos against the search vocabulary.
+func resolveIngredientTerms(raw string) []ingredientSearchTerm {
+	vocabulary := searchVocabulary()
+
+	var resolved []ingredientSearchTerm
+	for _, query := range strings.Split(raw, ",") {
+		query = strings.TrimSpace(query)
+		term, corrected := utils.CorrectIngredientTerm(query, vocabulary)
 		if term == "" {
 			continue
 		}
-		if i == 0 {
-			query = query.Where("LOWER(ingredients) LIKE ?", "%"+term+"%")
-		} else {
-			query = query.Or("LOWER(ingredients) LIKE ?", "%"+term+"%")
+		resolved = append(resolved, ingredientSearchTerm{Query: query, Term: term, Corrected: corrected})
+	}
+	return resolved
+}
+
+type pantryMatch struct {
+	Recipe       models.Recipe `json:"recipe"`
+	Coverage     float64       `json:"coverage"`
+	MatchedCount int           `json:"matched_count"`
+	MissingCount int           `json:"missing_count"`
+	Matched      []string      `json:"matched_ingredients"`
+	Missing      []string      `json:"missing_ingredients"`
+}
+
+// pantrySearch ranks recipes by the fraction of their ingredients covered by
+// the caller's pantry. With match=all every pantry term must appear in the
+// recipe; with match=any (the default) one is enough. max_missing caps the
+// number of uncovered ingredients and exclude drops recipes using any of the
+// listed ingredients.
+func pantrySearch(c *gin.Context, ingredientsParam string, resolved []ingredientSearchTerm, terms []string) {
+	match := c.DefaultQuery("match", "any")
+	if match != "any" && match != "all" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "match must be one of: all, any")
+		return
+	}
+
+	maxMissing := -1
+	if raw := c.Query("max_missing"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 0 {
+			utils.ErrorResponse(c, http.StatusBadRequest, "max_missing must be a non-negative whole number")
+			return
 		}
+		maxMissing = parsed
 	}
 
-	result := query.Find(&recipes)
+	query := db.DB.Where("recipes.id IN (?)", recipesWithIngredients(terms))
+	if excluded := ingredientTerms(c.Query("exclude")); len(excluded) > 0 {
+		query = query.Where("recipes.id NOT IN (?)", recipesWithIngredients(excluded))
+	}
+
+	query, ok := filterRecipes(c, query)
+	if !ok {
+		return
+	}
+
+	var recipes []models.Recipe
+	result := query.Preload("IngredientItems", orderedIngredients).
+		Find(&recipes)
 	if result.Error != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError,
 			"Search failed: "+result.Error.Error())
 		return
 	}
 
+	matches := make([]pantryMatch, 0, len(recipes))
+	matchedTerms := make(map[string]bool, len(terms))
+	for _, recipe := range recipes {
+		m := pantryMatch{Recipe: recipe, Matched: []string{}, Missing: []string{}}
+		used := make(map[string]bool, len(terms))
+		for _, item := range recipe.IngredientItems {
+			found := false
+			for _, term := range terms {
+				if ingredientHasTerm(item.SearchName, term) {
+					used[term] = true
+					found = true
+				}
+			}
+			if found {
+				m.Matched = append(m.Matched, item.Name)
+			} else {
+				m.Missing = append(m.Missing, item.Name)
+			}
+		}
+
+		if match == "all" && len(used) < len(terms) {
+			continue
+		}
+		m.MatchedCount, m.MissingCount = len(m.Matched), len(m.Missing)
+		if maxMissing >= 0 && m.MissingCount > maxMissing {
+			continue
+		}
+		for term := range used {
+			matchedTerms[term] = true
+		}
+		if total := len(recipe.IngredientItems); total > 0 {
+			m.Coverage = math.Round(float64(m.MatchedCount)/float64(total)*1000) / 1000
+		}
+		matches = append(matches, m)
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if a.Coverage != b.Coverage {
+			return a.Coverage > b.Coverage
+		}
+		if a.MissingCount != b.MissingCount {
+			return a.MissingCount < b.MissingCount
+		}
+		return a.Recipe.WeightedRating > b.Recipe.WeightedRating
+	})
+
+	matched := []string{}
+	for _, term := range terms {
+		if matchedTerms[term] {
+			matched = append(matched, term)
+		}
+	}
+
 	utils.SuccessResponse(c, http.StatusOK,
-		"Search results fetched successfully", gin.H{
-			"query":   ingredientsParam,
-			"count":   len(recipes),
-			"recipes": recipes,
+		"Pantry search results fetched successfully", gin.H{
+			"query":         ingredientsParam,
+			"terms":         resolved,
+			"matched_terms": matched,
+			"mode":          "pantry",
+			"match":         match,
+			"count":         len(matches),
+			"results":       matches,
 		})
 }
 
+// ingredientTerms splits a comma-separated ingredient list into normalized,
+// de-duplicated search terms without typo correction.
+func ingredientTerms(raw string) []string {
+	var terms []string
+	for _, term := range strings.Split(raw, ",") {
+		if term = utils.NormalizeIngredientName(term); term != "" {
+			terms = append(terms, term)
+		}
+	}
+	return uniqueStrings(terms)
+}
+
+// recipesWithIngredients selects the IDs of recipes that have an ingredient
+// whose normalized name contains any of the terms as whole words.
+func recipesWithIngredients(terms []string) *gorm.DB {
+	conditions := make([]string, len(terms))
+	args := make([]interface{}, len(terms))
+	for i, term := range terms {
+		conditions[i] = "(' ' || search_name || ' ') LIKE ?"
+		args[i] = "% " + term + " %"
+	}
+	return db.DB.Model(&models.RecipeIngredient{}).
+		Select("recipe_id").
+		Where(strings.Join(conditions, " OR "), args...)
+}
+
+// ingredientHasTerm mirrors the whole-word LIKE used by recipesWithIngredients.
+func ingredientHasTerm(canonicalName, term string) bool {
+	return strings.Contains(" "+canonicalName+" ", " "+term+" ")
+}
+
+// editableRecipeFields whitelists the keys UpdateRecipe passes on to GORM.
+// Everything else is dropped, including Go field names such as UserID that
+// GORM would otherwise match against columns too.
+var editableRecipeFields = map[string]bool{
+	"title":       true,
+	"description": true,
+	"ingredients": true,
+	"prep_time":   true,
+	"cook_time":   true,
+	"servings":    true,
+	"visibility":  true,
+	"publish_at":  true,
+}
+
 func UpdateRecipe(c *gin.Context) {
 	id := c.Param("id")
 	var recipe models.Recipe
 
-	if err := db.DB.First(&recipe, "id = ?", id).Error; err != nil {
+	if err := db.DB.Scopes(readableRecipes(currentUser(c))).First(&recipe, "id = ?", id).Error; err != nil {
 		utils.ErrorResponse(c, http.StatusNotFound, "Recipe not found")
 		return
 	}
 
+	if !canModifyRecipe(currentUser(c), &recipe, models.PermRecipesUpdateAny) {
+		utils.ErrorResponse(c, http.StatusForbidden, "You can only update your own recipes")
+		return
+	}
+
 	var updateData map[string]interface{}
 	if err := c.ShouldBindJSON(&updateData); err != nil {
 		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body: "+err.Error())
 		return
 	}
 
-	delete(updateData, "id")
-	delete(updateData, "created_at")
-	delete(updateData, "average_rating")
+	for key := range updateData {
+		if !editableRecipeFields[key] {
+			delete(updateData, key)
+		}
+	}
 
-	result := db.DB.Model(&recipe).Updates(updateData)
-	if result.Error != nil {
+	if !applyPublishingUpdate(c, &recipe, updateData) {
+		return
+	}
+
+	var ingredientItems []models.RecipeIngredient
+	rawIngredients, replaceIngredients := updateData["ingredients"]
+	if replaceIngredients {
+		raw, ok := rawIngredients.(string)
+		if !ok {
+			encoded, _ := json.Marshal(rawIngredients)
+			raw = string(encoded)
+		}
+		items, err := parseIngredients(raw)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		ingredientItems = items
+		updateData["ingredients"] = ingredientsJSON(items)
+		updateData["labels"] = dietaryLabels(items)
+	}
+
+	user := currentUser(c)
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := ensureBaselineRevision(tx, &recipe); err != nil {
+			return err
+		}
+		if err := tx.Model(&recipe).Updates(updateData).Error; err != nil {
+			return err
+		}
+		if replaceIngredients {
+			if err := replaceRecipeIngredients(tx, recipe.ID, ingredientItems); err != nil {
+				return err
+			}
+			if err := pruneStepIngredientRefs(tx, recipe.ID); err != nil {
+				return err
+			}
+			if err := models.ReindexRecipe(tx, recipe.ID); err != nil {
+				return err
+			}
+		}
+		return recordRevision(tx, recipe.ID, user.ID, models.RevisionActionUpdate, nil)
+	})
+	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError,
-			"Failed to update recipe: "+result.Error.Error())
+			"Failed to update recipe: "+err.Error())
 		return
 	}
 
-	db.DB.First(&recipe, "id = ?", id)
+	db.DB.Preload("IngredientItems", orderedIngredients).First(&recipe, "id = ?", id)
 	utils.SuccessResponse(c, http.StatusOK, "Recipe updated successfully", recipe)
 }
 
@@ -190,13 +809,18 @@ func DeleteRecipe(c *gin.Context) {
 	id := c.Param("id")
 	var recipe models.Recipe
 
-	if err := db.DB.First(&recipe, "id = ?", id).Error; err != nil {
+	if err := db.DB.Scopes(readableRecipes(currentUser(c))).First(&recipe, "id = ?", id).Error; err != nil {
 		utils.ErrorResponse(c, http.StatusNotFound, "Recipe not found")
 		return
 	}
 
-	db.DB.Where("recipe_id = ?", id).Delete(&models.Rating{})
+	if !canModifyRecipe(currentUser(c), &recipe, models.PermRecipesDeleteAny) {
+		utils.ErrorResponse(c, http.StatusForbidden, "You can only delete your own recipes")
+		return
+	}
 
+	// Deleting only moves the recipe to the trash; its ratings, ingredients
+	// and image stay until the purge job removes them.
 	result := db.DB.Delete(&recipe)
 	if result.Error != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError,
@@ -204,5 +828,262 @@ func DeleteRecipe(c *gin.Context) {
 		return
 	}
 
-	utils.SuccessResponse(c, http.StatusOK, "Recipe deleted successfully", nil)
+	utils.SuccessResponse(c, http.StatusOK, "Recipe moved to trash 🗑️", newTrashedRecipe(recipe))
+}
+
+func canModifyRecipe(user *models.User, recipe *models.Recipe, overridePermission string) bool {
+	if user == nil {
+		return false
+	}
+	return user.Can(overridePermission) || (recipe.UserID != "" && recipe.UserID == user.ID)
+}
+
+// listedRecipes limits a list or search to public recipes plus the caller's
+// own, whatever their visibility.
+func listedRecipes(user *models.User) func(*gorm.DB) *gorm.DB {
+	return func(tx *gorm.DB) *gorm.DB {
+		if user == nil {
+			return tx.Where("recipes.visibility = ?", models.VisibilityPublic)
+		}
+		return tx.Where("(recipes.visibility = ? OR recipes.user_id = ?)", models.VisibilityPublic, user.ID)
+	}
+}
+
+// readableRecipes limits a lookup by ID to recipes the caller may open:
+// public and unlisted ones, their own, or any recipe for users allowed to
+// edit other people's recipes. Drafts and private recipes of others look
+// like they do not exist.
+func readableRecipes(user *models.User) func(*gorm.DB) *gorm.DB {
+	readable := []string{models.VisibilityPublic, models.VisibilityUnlisted}
+	return func(tx *gorm.DB) *gorm.DB {
+		switch {
+		case user == nil:
+			return tx.Where("recipes.visibility IN ?", readable)
+		case user.Can(models.PermRecipesUpdateAny):
+			return tx
+		default:
+			return tx.Where("(recipes.visibility IN ? OR recipes.user_id = ?)", readable, user.ID)
+		}
+	}
+}
+
+func canReadRecipe(user *models.User, recipe *models.Recipe) bool {
+	return recipe.IsReadable() || canModifyRecipe(user, recipe, models.PermRecipesUpdateAny)
+}
+
+// parsePublishAt reads an RFC 3339 publish_at value. Times are kept in UTC so
+// SQLite compares them correctly against the scheduler's clock.
+func parsePublishAt(raw string) (*time.Time, error) {
+	if raw = strings.TrimSpace(raw); raw == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return nil, errors.New("publish_at must be an RFC 3339 timestamp, e.g. 2025-01-31T09:00:00Z")
+	}
+	t = t.UTC()
+	return &t, nil
+}
+
+// checkPublishing validates the visibility and publish_at a recipe is about
+// to have. A publish_at makes the scheduler turn the recipe public, so it
+// only makes sense on recipes that are not public yet.
+func checkPublishing(visibility string, publishAt *time.Time) error {
+	if !models.IsValidVisibility(visibility) {
+		return errors.New("Invalid visibility. Use one of: " + strings.Join(models.Visibilities, ", "))
+	}
+	if publishAt != nil && visibility == models.VisibilityPublic {
+		return errors.New("publish_at can only be set on draft, private or unlisted recipes")
+	}
+	return nil
+}
+
+// applyPublishingUpdate validates visibility and publish_at in an update body
+// against the recipe's current state and normalises them for Updates. Making
+// a recipe public clears any pending publish_at. It writes a 400 and returns
+// false on invalid input.
+func applyPublishingUpdate(c *gin.Context, recipe *models.Recipe, updateData map[string]interface{}) bool {
+	visibility, publishAt := recipe.Visibility, recipe.PublishAt
+
+	if raw, ok := updateData["visibility"]; ok {
+		value, isString := raw.(string)
+		if !isString {
+			utils.ErrorResponse(c, http.StatusBadRequest, "visibility must be a string")
+			return false
+		}
+		visibility = value
+		if _, scheduling := updateData["publish_at"]; !scheduling && visibility == models.VisibilityPublic {
+			publishAt = nil
+			updateData["publish_at"] = nil
+		}
+	}
+
+	if raw, ok := updateData["publish_at"]; ok && raw != nil {
+		value, isString := raw.(string)
+		if !isString {
+			utils.ErrorResponse(c, http.StatusBadRequest, "publish_at must be an RFC 3339 timestamp or null")
+			return false
+		}
+		parsed, err := parsePublishAt(value)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return false
+		}
+		publishAt = parsed
+		if parsed == nil {
+			updateData["publish_at"] = nil
+		} else {
+			updateData["publish_at"] = *parsed
+		}
+	} else if ok {
+		publishAt = nil
+	}
+
+	if err := checkPublishing(visibility, publishAt); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return false
+	}
+	return true
+}
+
+// parseIngredients accepts either a JSON array of free-text lines
+// (["2 1/2 cups flour, sifted","3 large eggs"]) or an array of
+// {quantity, quantity_max, unit, name, note} objects, and returns ordered
+// ingredient rows. Lines are run through the ingredient parser.
+func parseIngredients(raw string) ([]models.RecipeIngredient, error) {
+	invalid := errors.New("Ingredients must be a JSON array of strings or objects. " +
+		"Example: [\"tomato\",\"onion\"] or [{\"quantity\":2,\"unit\":\"cup\",\"name\":\"flour\"}]")
+
+	var elements []json.RawMessage
+	if err := json.Unmarshal([]byte(raw), &elements); err != nil {
+		return nil, invalid
+	}
+
+	items := make([]models.RecipeIngredient, 0, len(elements))
+	for _, element := range elements {
+		var item models.RecipeIngredient
+
+		var line string
+		if err := json.Unmarshal(element, &line); err == nil {
+			item = models.IngredientFromLine(line)
+		} else {
+			var input models.IngredientInput
+			if err := json.Unmarshal(element, &input); err != nil {
+				return nil, invalid
+			}
+			item = models.RecipeIngredient{
+				Quantity:    input.Quantity,
+				QuantityMax: input.QuantityMax,
+				Unit:        strings.TrimSpace(input.Unit),
+				Name:        strings.TrimSpace(input.Name),
+				Note:        strings.TrimSpace(input.Note),
+			}
+		}
+
+		if item.Name == "" {
+			continue
+		}
+		if err := utils.ValidateQuantity(item.Quantity, item.QuantityMax); err != nil {
+			return nil, errors.New("Invalid amount for ingredient \"" + item.Name + "\": " + err.Error())
+		}
+		item.Position = len(items)
+		items = append(items, item)
+	}
+
+	if len(items) == 0 {
+		return nil, errors.New("At least one ingredient is required")
+	}
+	return items, nil
+}
+
+// ingredientsJSON renders ingredient rows as the legacy JSON array of strings
+// kept in recipes.ingredients for older clients.
+func ingredientsJSON(items []models.RecipeIngredient) string {
+	lines := make([]string, len(items))
+	for i, item := range items {
+		lines[i] = item.String()
+	}
+	encoded, _ := json.Marshal(lines)
+	return string(encoded)
+}
+
+func dietaryLabels(items []models.RecipeIngredient) models.StringList {
+	names := make([]string, len(items))
+	for i, item := range items {
+		names[i] = item.Name
+	}
+	return utils.DietaryLabels(names)
+}
+
+func orderedIngredients(tx *gorm.DB) *gorm.DB {
+	return tx.Order("position ASC")
+}
+
+// replaceRecipeIngredients makes items the recipe's ingredient list. Rows are
+// updated in place where possible so their IDs, and the step references to
+// them, survive an edit: a new item first reuses an existing row with the
+// same canonical name, then the unclaimed row at its position. Rows left
+// over are deleted; items without a row are created. On return every item
+// carries its row ID.
+func replaceRecipeIngredients(tx *gorm.DB, recipeID string, items []models.RecipeIngredient) error {
+	var existing []models.RecipeIngredient
+	if err := tx.Scopes(orderedIngredients).Where("recipe_id = ?", recipeID).Find(&existing).Error; err != nil {
+		return err
+	}
+
+	claimed := make([]bool, len(existing))
+	rowFor := make([]int, len(items))
+	for i := range items {
+		items[i].RecipeID = recipeID
+		items[i].ID = ""
+		rowFor[i] = -1
+		canonical := utils.CanonicalIngredientName(items[i].Name)
+		for j, row := range existing {
+			if !claimed[j] && row.CanonicalName == canonical {
+				claimed[j], rowFor[i] = true, j
+				break
+			}
+		}
+	}
+	for i := range items {
+		if rowFor[i] < 0 && i < len(existing) && !claimed[i] {
+			claimed[i], rowFor[i] = true, i
+		}
+	}
+
+	for j, row := range existing {
+		if !claimed[j] {
+			if err := tx.Delete(&row).Error; err != nil {
+				return err
+			}
+		}
+	}
+	for i := range items {
+		if rowFor[i] < 0 {
+			if err := tx.Create(&items[i]).Error; err != nil {
+				return err
+			}
+			continue
+		}
+		items[i].ID = existing[rowFor[i]].ID
+		if err := tx.Save(&items[i]).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// findEditableRecipe loads the recipe named by the :id route param and checks
+// that the current user may modify it, writing the error response otherwise.
+func findEditableRecipe(c *gin.Context) (models.Recipe, bool) {
+	var recipe models.Recipe
+	if err := db.DB.Scopes(readableRecipes(currentUser(c))).First(&recipe, "id = ?", c.Param("id")).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "Recipe not found")
+		return recipe, false
+	}
+	if !canModifyRecipe(currentUser(c), &recipe, models.PermRecipesUpdateAny) {
+		utils.ErrorResponse(c, http.StatusForbidden, "You can only modify your own recipes")
+		return recipe, false
+	}
+	return recipe, true
 }
@@ -0,0 +1,145 @@
+package controllers_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"recipe-api/src/db"
+	"recipe-api/src/models"
+)
+
+func TestRecipeRangeFiltersRejectInvalidNumbers(t *testing.T) {
+	router := newTestServer(t)
+
+	tests := []struct {
+		query string
+		want  int
+	}{
+		{"max_prep_time=30", http.StatusOK},
+		{"min_rating=4.5", http.StatusOK},
+		{"min_rating=NaN", http.StatusBadRequest},
+		{"max_prep_time=nan", http.StatusBadRequest},
+		{"min_servings=Inf", http.StatusBadRequest},
+		{"max_cook_time=-Infinity", http.StatusBadRequest},
+		{"min_total_time=-1", http.StatusBadRequest},
+		{"min_rating=6", http.StatusBadRequest},
+		{"max_servings=lots", http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/recipes?"+tt.query, nil))
+			if w.Code != tt.want {
+				t.Errorf("status %d, want %d; body %s", w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestRecipeVisibilityIsAlwaysValidated(t *testing.T) {
+	router := newTestServer(t)
+
+	owner, token := newTestUser(t, "owner")
+	recipe := models.Recipe{Title: "Soup", UserID: owner.ID, Ingredients: "[]"}
+	if err := db.DB.Create(&recipe).Error; err != nil {
+		t.Fatalf("create recipe: %v", err)
+	}
+
+	for _, body := range []string{`{"visibility":"bogus"}`, `{"visibility":5}`} {
+		req := httptest.NewRequest(http.MethodPut, "/api/recipes/"+recipe.ID, bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("PUT %s: status %d, want 400; body %s", body, w.Code, w.Body.String())
+		}
+	}
+
+	// Writes that skip the handlers are stopped by the model.
+	writes := map[string]error{
+		"create":  db.DB.Create(&models.Recipe{Title: "Stew", UserID: owner.ID, Ingredients: "[]", Visibility: "bogus"}).Error,
+		"update":  db.DB.Model(&recipe).Update("visibility", "bogus").Error,
+		"updates": db.DB.Model(&recipe).Updates(map[string]interface{}{"Visibility": "bogus"}).Error,
+		"save":    db.DB.Save(&models.Recipe{ID: recipe.ID, Title: "Soup", UserID: owner.ID, Ingredients: "[]", Visibility: "bogus"}).Error,
+	}
+	for name, err := range writes {
+		if !errors.Is(err, models.ErrInvalidVisibility) {
+			t.Errorf("%s: err = %v, want ErrInvalidVisibility", name, err)
+		}
+	}
+
+	var stored models.Recipe
+	if err := db.DB.First(&stored, "id = ?", recipe.ID).Error; err != nil {
+		t.Fatalf("reload recipe: %v", err)
+	}
+	if stored.Visibility != models.VisibilityPublic {
+		t.Errorf("visibility = %q, want %q", stored.Visibility, models.VisibilityPublic)
+	}
+}
+
+func TestUpdateRecipeIgnoresReadOnlyFields(t *testing.T) {
+	router := newTestServer(t)
+
+	owner, token := newTestUser(t, "owner")
+	other, _ := newTestUser(t, "other")
+	recipe := models.Recipe{
+		Title:          "Soup",
+		UserID:         owner.ID,
+		Ingredients:    "[]",
+		ImageURL:       "/uploads/soup.jpg",
+		AverageRating:  4.5,
+		RatingCount:    2,
+		WeightedRating: 3.9,
+		Labels:         models.StringList{"vegan"},
+	}
+	if err := db.DB.Create(&recipe).Error; err != nil {
+		t.Fatalf("create recipe: %v", err)
+	}
+
+	// Both JSON and Go field names, since GORM matches either against columns.
+	body := `{
+		"title": "Better Soup",
+		"AverageRating": 1, "average_rating": 1,
+		"RatingCount": 99, "rating_count": 99,
+		"WeightedRating": 5, "weighted_rating": 5,
+		"Labels": ["meat"], "labels": ["meat"],
+		"UserID": "` + other.ID + `", "user_id": "` + other.ID + `",
+		"ImageURL": "/uploads/evil.jpg", "image_url": "/uploads/evil.jpg",
+		"ForkedFromID": "elsewhere", "forked_from": "elsewhere",
+		"Visibility": "bogus",
+		"DeletedAt": "2024-01-01T00:00:00Z", "deleted_at": "2024-01-01T00:00:00Z"
+	}`
+	req := httptest.NewRequest(http.MethodPut, "/api/recipes/"+recipe.ID, bytes.NewBufferString(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status %d, body %s", w.Code, w.Body.String())
+	}
+
+	var stored models.Recipe
+	if err := db.DB.First(&stored, "id = ?", recipe.ID).Error; err != nil {
+		t.Fatalf("reload recipe (soft-deleted?): %v", err)
+	}
+	if stored.Title != "Better Soup" {
+		t.Errorf("title = %q, want the update applied", stored.Title)
+	}
+	if stored.AverageRating != 4.5 || stored.RatingCount != 2 || stored.WeightedRating != 3.9 {
+		t.Errorf("rating stats changed: average %v, count %d, weighted %v",
+			stored.AverageRating, stored.RatingCount, stored.WeightedRating)
+	}
+	if len(stored.Labels) != 1 || stored.Labels[0] != "vegan" {
+		t.Errorf("labels = %v, want [vegan]", stored.Labels)
+	}
+	if stored.UserID != owner.ID || stored.ImageURL != "/uploads/soup.jpg" || stored.ForkedFromID != nil {
+		t.Errorf("owner, image or fork origin changed: %q, %q, %v", stored.UserID, stored.ImageURL, stored.ForkedFromID)
+	}
+	if stored.Visibility != models.VisibilityPublic {
+		t.Errorf("visibility = %q, want %q", stored.Visibility, models.VisibilityPublic)
+	}
+}
@@ -0,0 +1,352 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"recipe-api/src/db"
+	"recipe-api/src/models"
+	"recipe-api/src/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type revisionChange struct {
+	Field   string      `json:"field"`
+	From    interface{} `json:"from"`
+	To      interface{} `json:"to"`
+	Added   []string    `json:"added,omitempty"`
+	Removed []string    `json:"removed,omitempty"`
+}
+
+func GetRevisions(c *gin.Context) {
+	recipeID := c.Param("id")
+	if err := db.DB.Scopes(readableRecipes(currentUser(c))).Select("id").First(&models.Recipe{}, "id = ?", recipeID).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "Recipe not found")
+		return
+	}
+
+	var revisions []models.RecipeRevision
+	result := db.DB.Preload("User").
+		Where("recipe_id = ?", recipeID).
+		Order("number DESC").
+		Find(&revisions)
+	if result.Error != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError,
+			"Failed to fetch revisions: "+result.Error.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Revisions fetched successfully", gin.H{
+		"recipe_id": recipeID,
+		"count":     len(revisions),
+		"revisions": revisions,
+	})
+}
+
+func GetRevision(c *gin.Context) {
+	revision, ok := findRevision(c, c.Param("rev"))
+	if !ok {
+		return
+	}
+	utils.SuccessResponse(c, http.StatusOK, "Revision fetched successfully", revision)
+}
+
+// DiffRevisions compares two revisions field by field. ?from= defaults to the
+// revision before ?to=, and ?to= to the latest revision. The first revision
+// has nothing before it and is compared with itself. A recipe that was never
+// edited has no revisions and answers 404.
+func DiffRevisions(c *gin.Context) {
+	recipeID := c.Param("id")
+
+	toParam := c.Query("to")
+	if toParam == "" {
+		var latest int
+		if err := db.DB.Model(&models.RecipeRevision{}).Where("recipe_id = ?", recipeID).
+			Select("COALESCE(MAX(number), 0)").Scan(&latest).Error; err != nil {
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to fetch revisions: "+err.Error())
+			return
+		}
+		if latest == 0 {
+			if err := db.DB.Scopes(readableRecipes(currentUser(c))).Select("id").First(&models.Recipe{}, "id = ?", recipeID).Error; err != nil {
+				utils.ErrorResponse(c, http.StatusNotFound, "Recipe not found")
+				return
+			}
+			utils.ErrorResponse(c, http.StatusNotFound, "Recipe has no revisions to compare yet")
+			return
+		}
+		toParam = strconv.Itoa(latest)
+	}
+	to, ok := findRevision(c, toParam)
+	if !ok {
+		return
+	}
+
+	fromParam := c.Query("from")
+	if fromParam == "" {
+		fromParam = strconv.Itoa(max(to.Number-1, 1))
+	}
+	from, ok := findRevision(c, fromParam)
+	if !ok {
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Revision diff computed successfully", gin.H{
+		"recipe_id": recipeID,
+		"from":      from.Number,
+		"to":        to.Number,
+		"changes":   diffSnapshots(from.Snapshot, to.Snapshot),
+	})
+}
+
+// RevertRecipe restores the content of an earlier revision. The revert is
+// itself recorded as a new revision, so it can be undone the same way.
+func RevertRecipe(c *gin.Context) {
+	recipe, ok := findEditableRecipe(c)
+	if !ok {
+		return
+	}
+	revision, ok := findRevision(c, c.Param("rev"))
+	if !ok {
+		return
+	}
+
+	snapshot := revision.Snapshot
+	items := snapshot.IngredientItems()
+	user := currentUser(c)
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := ensureBaselineRevision(tx, &recipe); err != nil {
+			return err
+		}
+		if err := tx.Model(&recipe).Updates(map[string]interface{}{
+			"title":       snapshot.Title,
+			"description": snapshot.Description,
+			"image_url":   snapshot.ImageURL,
+			"prep_time":   snapshot.PrepTime,
+			"cook_time":   snapshot.CookTime,
+			"servings":    snapshot.Servings,
+			"ingredients": ingredientsJSON(items),
+			"labels":      dietaryLabels(items),
+		}).Error; err != nil {
+			return err
+		}
+		if err := replaceRecipeIngredients(tx, recipe.ID, items); err != nil {
+			return err
+		}
+		if err := pruneStepIngredientRefs(tx, recipe.ID); err != nil {
+			return err
+		}
+		if err := restoreSnapshotSteps(tx, recipe.ID, snapshot, items); err != nil {
+			return err
+		}
+		if err := restoreSnapshotTags(tx, &recipe, snapshot); err != nil {
+			return err
+		}
+		if err := models.ReindexRecipe(tx, recipe.ID); err != nil {
+			return err
+		}
+		return recordRevision(tx, recipe.ID, user.ID, models.RevisionActionRevert, &revision.Number)
+	})
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError,
+			"Failed to revert recipe: "+err.Error())
+		return
+	}
+
+	db.DB.Preload("IngredientItems", orderedIngredients).Preload("Steps", orderedSteps).Preload("Tags").
+		First(&recipe, "id = ?", recipe.ID)
+	utils.SuccessResponse(c, http.StatusOK,
+		"Recipe reverted to revision "+strconv.Itoa(revision.Number)+" ⏪", recipe)
+}
+
+// restoreSnapshotSteps replaces the recipe's steps with those of snapshot.
+// items are the restored ingredient rows, in snapshot order. Snapshots
+// without steps leave the current ones alone.
+func restoreSnapshotSteps(tx *gorm.DB, recipeID string, snapshot models.RecipeSnapshot, items []models.RecipeIngredient) error {
+	if snapshot.Steps == nil {
+		return nil
+	}
+	if err := tx.Where("recipe_id = ?", recipeID).Delete(&models.RecipeStep{}).Error; err != nil {
+		return err
+	}
+	for _, step := range snapshot.StepItems(recipeID, items) {
+		if err := tx.Create(&step).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// restoreSnapshotTags reattaches the tags of snapshot that still exist.
+// Snapshots without tags leave the current ones alone.
+func restoreSnapshotTags(tx *gorm.DB, recipe *models.Recipe, snapshot models.RecipeSnapshot) error {
+	if snapshot.Tags == nil {
+		return nil
+	}
+	tagIDs := make([]string, len(snapshot.Tags))
+	for i, tag := range snapshot.Tags {
+		tagIDs[i] = tag.ID
+	}
+	tags := []models.Tag{}
+	if len(tagIDs) > 0 {
+		if err := tx.Where("id IN ?", tagIDs).Find(&tags).Error; err != nil {
+			return err
+		}
+	}
+	return tx.Model(recipe).Association("Tags").Replace(tags)
+}
+
+func findRevision(c *gin.Context, number string) (models.RecipeRevision, bool) {
+	var revision models.RecipeRevision
+	n, err := strconv.Atoi(number)
+	if err != nil || n < 1 {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Revision numbers are whole numbers starting at 1")
+		return revision, false
+	}
+	readable := db.DB.Model(&models.Recipe{}).Select("id").Scopes(readableRecipes(currentUser(c)))
+	err = db.DB.Preload("User").
+		Where("recipe_id IN (?)", readable).
+		First(&revision, "recipe_id = ? AND number = ?", c.Param("id"), n).Error
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "Revision not found")
+		return revision, false
+	}
+	return revision, true
+}
+
+// recordRevision snapshots the recipe as currently stored in tx.
+func recordRevision(tx *gorm.DB, recipeID, userID, action string, revertedFrom *int) error {
+	var recipe models.Recipe
+	if err := tx.Preload("IngredientItems", orderedIngredients).Preload("Steps", orderedSteps).Preload("Tags").
+		First(&recipe, "id = ?", recipeID).Error; err != nil {
+		return err
+	}
+
+	var latest int
+	if err := tx.Model(&models.RecipeRevision{}).Where("recipe_id = ?", recipeID).
+		Select("COALESCE(MAX(number), 0)").Scan(&latest).Error; err != nil {
+		return err
+	}
+
+	return tx.Create(&models.RecipeRevision{
+		RecipeID:     recipeID,
+		Number:       latest + 1,
+		UserID:       userID,
+		Action:       action,
+		RevertedFrom: revertedFrom,
+		Snapshot:     models.SnapshotRecipe(&recipe),
+	}).Error
+}
+
+// editWithRevision applies change to recipe in a transaction and records the
+// result as an update revision by user.
+func editWithRevision(recipe *models.Recipe, user *models.User, change func(tx *gorm.DB) error) error {
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := ensureBaselineRevision(tx, recipe); err != nil {
+			return err
+		}
+		if err := change(tx); err != nil {
+			return err
+		}
+		return recordRevision(tx, recipe.ID, user.ID, models.RevisionActionUpdate, nil)
+	})
+}
+
+// ensureBaselineRevision records the current state of recipes created before
+// revisions existed, so their first edit can still be reverted.
+func ensureBaselineRevision(tx *gorm.DB, recipe *models.Recipe) error {
+	var count int64
+	if err := tx.Model(&models.RecipeRevision{}).Where("recipe_id = ?", recipe.ID).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+	return recordRevision(tx, recipe.ID, recipe.UserID, models.RevisionActionBaseline, nil)
+}
+
+func diffSnapshots(from, to models.RecipeSnapshot) []revisionChange {
+	changes := []revisionChange{}
+	compare := func(field string, a, b interface{}) {
+		if a != b {
+			changes = append(changes, revisionChange{Field: field, From: a, To: b})
+		}
+	}
+	compare("title", from.Title, to.Title)
+	compare("description", from.Description, to.Description)
+	compare("image_url", from.ImageURL, to.ImageURL)
+	compare("prep_time", from.PrepTime, to.PrepTime)
+	compare("cook_time", from.CookTime, to.CookTime)
+	compare("servings", from.Servings, to.Servings)
+
+	compareLines := func(field string, before, after []string) {
+		if strings.Join(before, "\n") != strings.Join(after, "\n") {
+			changes = append(changes, revisionChange{
+				Field:   field,
+				From:    before,
+				To:      after,
+				Added:   missingFrom(after, before),
+				Removed: missingFrom(before, after),
+			})
+		}
+	}
+	compareLines("ingredients", snapshotLines(from), snapshotLines(to))
+
+	// Revisions from before steps and tags were captured have neither.
+	if from.Steps != nil && to.Steps != nil {
+		compareLines("steps", stepLines(from), stepLines(to))
+	}
+	if from.Tags != nil && to.Tags != nil {
+		compareLines("tags", tagNames(from), tagNames(to))
+	}
+	return changes
+}
+
+func snapshotLines(snapshot models.RecipeSnapshot) []string {
+	items := snapshot.IngredientItems()
+	lines := make([]string, len(items))
+	for i, item := range items {
+		lines[i] = item.String()
+	}
+	return lines
+}
+
+func stepLines(snapshot models.RecipeSnapshot) []string {
+	lines := make([]string, len(snapshot.Steps))
+	for i, step := range snapshot.Steps {
+		lines[i] = step.Instruction
+		if step.DurationMinutes != nil {
+			lines[i] += " (" + strconv.Itoa(*step.DurationMinutes) + " min)"
+		}
+	}
+	return lines
+}
+
+func tagNames(snapshot models.RecipeSnapshot) []string {
+	names := make([]string, len(snapshot.Tags))
+	for i, tag := range snapshot.Tags {
+		names[i] = tag.Name
+	}
+	return names
+}
+
+// missingFrom returns the lines of a that do not appear in b, respecting
+// duplicates.
+func missingFrom(a, b []string) []string {
+	remaining := make(map[string]int, len(b))
+	for _, line := range b {
+		remaining[line]++
+	}
+	var out []string
+	for _, line := range a {
+		if remaining[line] > 0 {
+			remaining[line]--
+			continue
+		}
+		out = append(out, line)
+	}
+	return out
+}
@@ -0,0 +1,48 @@
+package controllers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"recipe-api/src/db"
+	"recipe-api/src/models"
+)
+
+func TestDiffRevisionsWithoutHistory(t *testing.T) {
+	router := newTestServer(t)
+
+	owner, _ := newTestUser(t, "owner")
+	recipe := models.Recipe{Title: "Soup", UserID: owner.ID, Ingredients: "[]"}
+	if err := db.DB.Create(&recipe).Error; err != nil {
+		t.Fatalf("create recipe: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		recipeID string
+		message  string
+	}{
+		{"never edited", recipe.ID, "Recipe has no revisions to compare yet"},
+		{"unknown recipe", "missing", "Recipe not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/recipes/"+tt.recipeID+"/revisions/diff", nil))
+			if w.Code != http.StatusNotFound {
+				t.Fatalf("status %d, want 404; body %s", w.Code, w.Body.String())
+			}
+			var body struct {
+				Message string `json:"message"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if body.Message != tt.message {
+				t.Errorf("message = %q, want %q", body.Message, tt.message)
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"recipe-api/src/db"
+	"recipe-api/src/models"
+	"recipe-api/src/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Column weights for bm25(), in table order: recipe_id (unindexed), title,
+// description, ingredients, tags. Title hits count most.
+const searchRankExpr = "bm25(" + models.RecipeSearchTable + ", 0, 10.0, 2.0, 5.0, 4.0)"
+
+type searchResult struct {
+	Recipe         models.Recipe `json:"recipe"`
+	Score          float64       `json:"score"`
+	TitleHighlight string        `json:"title_highlight"`
+	Snippet        string        `json:"snippet"`
+}
+
+// SearchRecipes runs a full-text query over titles, descriptions, ingredient
+// names and tags. Words are ANDed together; a trailing * makes a word a
+// prefix query ("choc*"). Results are ordered by BM25 relevance and limited to
+// public recipes plus the caller's own. Builds without FTS5 fall back to
+// searchRecipesLike.
+func SearchRecipes(c *gin.Context) {
+	q := c.Query("q")
+	match := ftsMatchExpression(q)
+	if match == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest,
+			"Please provide a search query. Example: ?q=chocolate cake or ?q=choc*")
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "10"))
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 || perPage > 100 {
+		perPage = 10
+	}
+
+	user := currentUser(c)
+	if !models.SearchIndexEnabled() {
+		searchRecipesLike(c, user, searchWords(q), page, perPage)
+		return
+	}
+
+	matches := func() *gorm.DB {
+		return db.DB.Table(models.RecipeSearchTable).
+			Joins("JOIN recipes ON recipes.id = "+models.RecipeSearchTable+".recipe_id").
+			Scopes(listedRecipes(user)).
+			Where(models.RecipeSearchTable+" MATCH ?", match)
+	}
+
+	var totalCount int64
+	if err := matches().Count(&totalCount).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Search failed: "+err.Error())
+		return
+	}
+
+	var hits []struct {
+		RecipeID       string
+		Relevance      float64
+		TitleHighlight string
+		Snippet        string
+	}
+	err := matches().
+		Select("recipe_id, " + searchRankExpr + " AS relevance, " +
+			"highlight(" + models.RecipeSearchTable + ", 1, '<mark>', '</mark>') AS title_highlight, " +
+			"snippet(" + models.RecipeSearchTable + ", -1, '<mark>', '</mark>', '…', 16) AS snippet").
+		Order("relevance").
+		Limit(perPage).
+		Offset((page - 1) * perPage).
+		Scan(&hits).Error
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Search failed: "+err.Error())
+		return
+	}
+
+	ids := make([]string, len(hits))
+	for i, hit := range hits {
+		ids[i] = hit.RecipeID
+	}
+	var recipes []models.Recipe
+	db.DB.Preload("Tags").Where("id IN ?", ids).Find(&recipes)
+	byID := make(map[string]models.Recipe, len(recipes))
+	for _, recipe := range recipes {
+		byID[recipe.ID] = recipe
+	}
+
+	results := make([]searchResult, 0, len(hits))
+	for _, hit := range hits {
+		recipe, ok := byID[hit.RecipeID]
+		if !ok {
+			continue
+		}
+		results = append(results, searchResult{
+			Recipe:         recipe,
+			Score:          -hit.Relevance,
+			TitleHighlight: hit.TitleHighlight,
+			Snippet:        hit.Snippet,
+		})
+	}
+
+	utils.PaginatedSuccessResponse(c, http.StatusOK,
+		"Search results fetched successfully", results, page, perPage, totalCount)
+}
+
+// searchRecipesLike answers SearchRecipes with plain LIKE matching when the
+// FTS5 index is unavailable. Every word must appear somewhere in the title,
+// description, an ingredient name or a tag name; there is no relevance, so
+// hits come newest first with a zero score, the plain title and the
+// description as snippet.
+func searchRecipesLike(c *gin.Context, user *models.User, words []string, page, perPage int) {
+	matches := func() *gorm.DB {
+		query := db.DB.Model(&models.Recipe{}).Scopes(listedRecipes(user))
+		for _, word := range words {
+			pattern := "%" + word + "%"
+			query = query.Where(`(LOWER(recipes.title) LIKE ? OR LOWER(recipes.description) LIKE ?
+				OR EXISTS (SELECT 1 FROM recipe_ingredients ri WHERE ri.recipe_id = recipes.id AND LOWER(ri.name) LIKE ?)
+				OR EXISTS (SELECT 1 FROM tags t JOIN recipe_tags rt ON rt.tag_id = t.id
+					WHERE rt.recipe_id = recipes.id AND LOWER(t.name) LIKE ?))`,
+				pattern, pattern, pattern, pattern)
+		}
+		return query
+	}
+
+	var totalCount int64
+	if err := matches().Count(&totalCount).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Search failed: "+err.Error())
+		return
+	}
+
+	var recipes []models.Recipe
+	err := matches().Preload("Tags").
+		Order("recipes.created_at DESC").
+		Limit(perPage).
+		Offset((page - 1) * perPage).
+		Find(&recipes).Error
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Search failed: "+err.Error())
+		return
+	}
+
+	results := make([]searchResult, len(recipes))
+	for i, recipe := range recipes {
+		results[i] = searchResult{
+			Recipe:         recipe,
+			TitleHighlight: recipe.Title,
+			Snippet:        recipe.Description,
+		}
+	}
+
+	utils.PaginatedSuccessResponse(c, http.StatusOK,
+		"Search results fetched successfully", results, page, perPage, totalCount)
+}
+
+// searchWords splits free text into the lowercase words used by the LIKE
+// fallback. Prefix markers are dropped since LIKE matches substrings anyway.
+func searchWords(q string) []string {
+	return strings.FieldsFunc(strings.ToLower(q), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// ftsMatchExpression turns free text into a safe FTS5 query: every word is
+// quoted so operators and column filters in user input are inert, and a
+// trailing * is kept as a prefix marker.
+func ftsMatchExpression(q string) string {
+	var terms []string
+	for _, field := range strings.Fields(strings.ToLower(q)) {
+		words := strings.FieldsFunc(field, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		for i, word := range words {
+			term := `"` + word + `"`
+			if i == len(words)-1 && strings.HasSuffix(field, "*") {
+				term += "*"
+			}
+			terms = append(terms, term)
+		}
+	}
+	return strings.Join(terms, " ")
+}
@@ -0,0 +1,84 @@
+package controllers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"recipe-api/src/db"
+	"recipe-api/src/models"
+)
+
+func TestSearchFallsBackToLikeWithoutFTS5(t *testing.T) {
+	router := newTestServer(t)
+	if models.SearchIndexEnabled() {
+		t.Skip("built with FTS5; the LIKE fallback is not used")
+	}
+
+	owner, token := newTestUser(t, "owner")
+	recipes := []models.Recipe{
+		{Title: "Chocolate Cake", Description: "Rich and dark", UserID: owner.ID, Ingredients: "[]"},
+		{Title: "Tomato Soup", Description: "Warming", UserID: owner.ID, Ingredients: "[]"},
+		{Title: "Secret Chocolate Mousse", UserID: owner.ID, Ingredients: "[]", Visibility: models.VisibilityPrivate},
+	}
+	for i := range recipes {
+		if err := db.DB.Create(&recipes[i]).Error; err != nil {
+			t.Fatalf("create recipe: %v", err)
+		}
+	}
+	basil := models.RecipeIngredient{ID: "basil", RecipeID: recipes[1].ID, Name: "Basil", CanonicalName: "basil"}
+	if err := db.DB.Create(&basil).Error; err != nil {
+		t.Fatalf("create ingredient: %v", err)
+	}
+
+	tests := []struct {
+		query  string
+		token  string
+		titles []string
+	}{
+		{"chocolate", "", []string{"Chocolate Cake"}},
+		{"choc*", token, []string{"Secret Chocolate Mousse", "Chocolate Cake"}},
+		{"tomato basil", "", []string{"Tomato Soup"}},
+		{"DARK cake", "", []string{"Chocolate Cake"}},
+		{"cake basil", "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/search?q="+url.QueryEscape(tt.query), nil)
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			if w.Code != http.StatusOK {
+				t.Fatalf("status %d, body %s", w.Code, w.Body.String())
+			}
+
+			var body struct {
+				Data []struct {
+					Recipe struct {
+						Title string `json:"title"`
+					} `json:"recipe"`
+				} `json:"data"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			var titles []string
+			for _, hit := range body.Data {
+				titles = append(titles, hit.Recipe.Title)
+			}
+			if len(titles) != len(tt.titles) {
+				t.Fatalf("titles = %q, want %q", titles, tt.titles)
+			}
+			for i := range titles {
+				if titles[i] != tt.titles[i] {
+					t.Errorf("titles = %q, want %q", titles, tt.titles)
+					break
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,295 @@
+package controllers
+
+import (
+	"net/http"
+
+	"recipe-api/src/db"
+	"recipe-api/src/models"
+	"recipe-api/src/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func GetSteps(c *gin.Context) {
+	recipeID := c.Param("id")
+
+	var recipe models.Recipe
+	if err := db.DB.Scopes(readableRecipes(currentUser(c))).First(&recipe, "id = ?", recipeID).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "Recipe not found")
+		return
+	}
+
+	var steps []models.RecipeStep
+	if err := db.DB.Scopes(orderedSteps).Where("recipe_id = ?", recipeID).Find(&steps).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError,
+			"Failed to fetch steps: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Steps fetched successfully", gin.H{
+		"recipe_id":       recipeID,
+		"count":           len(steps),
+		"total_step_time": totalStepMinutes(steps),
+		"steps":           steps,
+	})
+}
+
+func AddStep(c *gin.Context) {
+	recipe, ok := findEditableRecipe(c)
+	if !ok {
+		return
+	}
+
+	var input models.StepInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest,
+			"Invalid step data. Instruction is required: "+err.Error())
+		return
+	}
+
+	input.IngredientIDs = uniqueStrings(input.IngredientIDs)
+	if !validateStepIngredients(c, recipe.ID, input.IngredientIDs) {
+		return
+	}
+
+	step := models.RecipeStep{
+		RecipeID:        recipe.ID,
+		Instruction:     input.Instruction,
+		DurationMinutes: input.DurationMinutes,
+		IngredientIDs:   input.IngredientIDs,
+	}
+
+	err := editWithRevision(&recipe, currentUser(c), func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&models.RecipeStep{}).Where("recipe_id = ?", recipe.ID).Count(&count).Error; err != nil {
+			return err
+		}
+
+		step.Position = int(count)
+		if input.Position != nil && *input.Position < int(count) {
+			step.Position = *input.Position
+			if err := tx.Model(&models.RecipeStep{}).
+				Where("recipe_id = ? AND position >= ?", recipe.ID, step.Position).
+				Update("position", gorm.Expr("position + 1")).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Create(&step).Error
+	})
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError,
+			"Failed to add step: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Step added successfully", step)
+}
+
+func UpdateStep(c *gin.Context) {
+	recipe, ok := findEditableRecipe(c)
+	if !ok {
+		return
+	}
+
+	var step models.RecipeStep
+	if err := db.DB.First(&step, "id = ? AND recipe_id = ?", c.Param("stepId"), recipe.ID).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "Step not found")
+		return
+	}
+
+	var input models.StepInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest,
+			"Invalid step data. Instruction is required: "+err.Error())
+		return
+	}
+
+	input.IngredientIDs = uniqueStrings(input.IngredientIDs)
+	if !validateStepIngredients(c, recipe.ID, input.IngredientIDs) {
+		return
+	}
+
+	ingredientIDs := models.StringList(input.IngredientIDs)
+	if ingredientIDs == nil {
+		ingredientIDs = models.StringList{}
+	}
+
+	err := editWithRevision(&recipe, currentUser(c), func(tx *gorm.DB) error {
+		return tx.Model(&step).Updates(map[string]interface{}{
+			"instruction":      input.Instruction,
+			"duration_minutes": input.DurationMinutes,
+			"ingredient_ids":   ingredientIDs,
+		}).Error
+	})
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError,
+			"Failed to update step: "+err.Error())
+		return
+	}
+
+	db.DB.First(&step, "id = ?", step.ID)
+	utils.SuccessResponse(c, http.StatusOK, "Step updated successfully", step)
+}
+
+func DeleteStep(c *gin.Context) {
+	recipe, ok := findEditableRecipe(c)
+	if !ok {
+		return
+	}
+
+	var step models.RecipeStep
+	if err := db.DB.First(&step, "id = ? AND recipe_id = ?", c.Param("stepId"), recipe.ID).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "Step not found")
+		return
+	}
+
+	err := editWithRevision(&recipe, currentUser(c), func(tx *gorm.DB) error {
+		if err := tx.Delete(&step).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.RecipeStep{}).
+			Where("recipe_id = ? AND position > ?", recipe.ID, step.Position).
+			Update("position", gorm.Expr("position - 1")).Error
+	})
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError,
+			"Failed to delete step: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Step deleted successfully", nil)
+}
+
+func ReorderSteps(c *gin.Context) {
+	recipe, ok := findEditableRecipe(c)
+	if !ok {
+		return
+	}
+
+	var input models.ReorderStepsInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest,
+			"Invalid reorder data. step_ids must list every step in the new order: "+err.Error())
+		return
+	}
+
+	var steps []models.RecipeStep
+	if err := db.DB.Where("recipe_id = ?", recipe.ID).Find(&steps).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError,
+			"Failed to fetch steps: "+err.Error())
+		return
+	}
+
+	known := make(map[string]bool, len(steps))
+	for _, step := range steps {
+		known[step.ID] = true
+	}
+	seen := make(map[string]bool, len(input.StepIDs))
+	for _, id := range input.StepIDs {
+		if !known[id] || seen[id] {
+			utils.ErrorResponse(c, http.StatusBadRequest,
+				"step_ids must contain each of the recipe's steps exactly once")
+			return
+		}
+		seen[id] = true
+	}
+	if len(seen) != len(known) {
+		utils.ErrorResponse(c, http.StatusBadRequest,
+			"step_ids must contain each of the recipe's steps exactly once")
+		return
+	}
+
+	err := editWithRevision(&recipe, currentUser(c), func(tx *gorm.DB) error {
+		for position, id := range input.StepIDs {
+			if err := tx.Model(&models.RecipeStep{}).
+				Where("id = ?", id).
+				Update("position", position).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError,
+			"Failed to reorder steps: "+err.Error())
+		return
+	}
+
+	if err := db.DB.Scopes(orderedSteps).Where("recipe_id = ?", recipe.ID).Find(&steps).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError,
+			"Failed to fetch steps: "+err.Error())
+		return
+	}
+	utils.SuccessResponse(c, http.StatusOK, "Steps reordered successfully", steps)
+}
+
+func orderedSteps(tx *gorm.DB) *gorm.DB {
+	return tx.Order("position ASC")
+}
+
+func totalStepMinutes(steps []models.RecipeStep) int {
+	total := 0
+	for _, step := range steps {
+		if step.DurationMinutes != nil {
+			total += *step.DurationMinutes
+		}
+	}
+	return total
+}
+
+// validateStepIngredients checks that every ID, which must already be
+// de-duplicated, is an ingredient of the recipe. It writes the error response
+// and reports false otherwise.
+func validateStepIngredients(c *gin.Context, recipeID string, ingredientIDs []string) bool {
+	if len(ingredientIDs) == 0 {
+		return true
+	}
+	var count int64
+	if err := db.DB.Model(&models.RecipeIngredient{}).
+		Where("recipe_id = ? AND id IN ?", recipeID, ingredientIDs).
+		Count(&count).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError,
+			"Failed to check step ingredients: "+err.Error())
+		return false
+	}
+	if int(count) != len(ingredientIDs) {
+		utils.ErrorResponse(c, http.StatusBadRequest,
+			"ingredient_ids must reference ingredients of this recipe")
+		return false
+	}
+	return true
+}
+
+// pruneStepIngredientRefs drops step references to ingredients that no longer
+// exist, e.g. lines removed when the recipe's ingredient list was replaced.
+func pruneStepIngredientRefs(tx *gorm.DB, recipeID string) error {
+	var ingredientIDs []string
+	if err := tx.Model(&models.RecipeIngredient{}).
+		Where("recipe_id = ?", recipeID).
+		Pluck("id", &ingredientIDs).Error; err != nil {
+		return err
+	}
+	existing := models.StringList(ingredientIDs)
+
+	var steps []models.RecipeStep
+	if err := tx.Where("recipe_id = ?", recipeID).Find(&steps).Error; err != nil {
+		return err
+	}
+	for _, step := range steps {
+		kept := models.StringList{}
+		for _, id := range step.IngredientIDs {
+			if existing.Contains(id) {
+				kept = append(kept, id)
+			}
+		}
+		if len(kept) == len(step.IngredientIDs) {
+			continue
+		}
+		if err := tx.Model(&step).Update("ingredient_ids", kept).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,236 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+
+	"recipe-api/src/db"
+	"recipe-api/src/models"
+	"recipe-api/src/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+func GetTags(c *gin.Context) {
+	var tags []models.Tag
+	query := db.DB.Order("type ASC, slug ASC")
+
+	if tagType := c.Query("type"); tagType != "" {
+		query = query.Where("type = ?", tagType)
+	}
+
+	if err := query.Find(&tags).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError,
+			"Failed to fetch tags: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Tags fetched successfully", tags)
+}
+
+func CreateTag(c *gin.Context) {
+	var input models.TagInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid tag data. Name is required: "+err.Error())
+		return
+	}
+
+	tag, ok := bindTag(c, input)
+	if !ok {
+		return
+	}
+
+	var existing models.Tag
+	if err := db.DB.Where("type = ? AND slug = ?", tag.Type, utils.Slugify(tag.Name)).
+		First(&existing).Error; err == nil {
+		utils.ErrorResponse(c, http.StatusConflict, "A "+tag.Type+" tag with this name already exists")
+		return
+	}
+
+	if err := db.DB.Create(&tag).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError,
+			"Failed to create tag: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Tag created successfully 🏷️", tag)
+}
+
+func UpdateTag(c *gin.Context) {
+	var tag models.Tag
+	if err := db.DB.First(&tag, "id = ?", c.Param("id")).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "Tag not found")
+		return
+	}
+
+	var input models.TagInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid tag data. Name is required: "+err.Error())
+		return
+	}
+	if input.Type == "" {
+		input.Type = tag.Type
+	}
+
+	updated, ok := bindTag(c, input)
+	if !ok {
+		return
+	}
+
+	var existing models.Tag
+	if err := db.DB.Where("type = ? AND slug = ? AND id <> ?", updated.Type, utils.Slugify(updated.Name), tag.ID).
+		First(&existing).Error; err == nil {
+		utils.ErrorResponse(c, http.StatusConflict, "A "+updated.Type+" tag with this name already exists")
+		return
+	}
+
+	tag.Name = updated.Name
+	tag.Type = updated.Type
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&tag).Error; err != nil {
+			return err
+		}
+		return reindexRecipes(tx, taggedRecipeIDs(tx, tag.ID))
+	})
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError,
+			"Failed to update tag: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Tag updated successfully", tag)
+}
+
+func DeleteTag(c *gin.Context) {
+	var tag models.Tag
+	if err := db.DB.First(&tag, "id = ?", c.Param("id")).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "Tag not found")
+		return
+	}
+
+	err := db.DB.Transaction(func(tx *gorm.DB) error {
+		recipeIDs := taggedRecipeIDs(tx, tag.ID)
+		if err := tx.Model(&tag).Association("Recipes").Clear(); err != nil {
+			return err
+		}
+		if err := tx.Delete(&tag).Error; err != nil {
+			return err
+		}
+		return reindexRecipes(tx, recipeIDs)
+	})
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError,
+			"Failed to delete tag: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Tag deleted successfully", nil)
+}
+
+func AttachTags(c *gin.Context) {
+	recipe, ok := findEditableRecipe(c)
+	if !ok {
+		return
+	}
+
+	var input models.AttachTagsInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request. tag_ids is required: "+err.Error())
+		return
+	}
+
+	var tags []models.Tag
+	db.DB.Where("id IN ?", input.TagIDs).Find(&tags)
+	if len(tags) != len(uniqueStrings(input.TagIDs)) {
+		utils.ErrorResponse(c, http.StatusBadRequest, "One or more tag_ids do not exist")
+		return
+	}
+
+	err := editWithRevision(&recipe, currentUser(c), func(tx *gorm.DB) error {
+		if err := tx.Model(&recipe).Association("Tags").Append(tags); err != nil {
+			return err
+		}
+		return models.ReindexRecipe(tx, recipe.ID)
+	})
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError,
+			"Failed to attach tags: "+err.Error())
+		return
+	}
+
+	db.DB.Model(&recipe).Association("Tags").Find(&recipe.Tags)
+	utils.SuccessResponse(c, http.StatusOK, "Tags attached successfully", recipe.Tags)
+}
+
+func DetachTag(c *gin.Context) {
+	recipe, ok := findEditableRecipe(c)
+	if !ok {
+		return
+	}
+
+	tag := models.Tag{ID: c.Param("tagId")}
+	err := editWithRevision(&recipe, currentUser(c), func(tx *gorm.DB) error {
+		if err := tx.Model(&recipe).Association("Tags").Delete(&tag); err != nil {
+			return err
+		}
+		return models.ReindexRecipe(tx, recipe.ID)
+	})
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError,
+			"Failed to detach tag: "+err.Error())
+		return
+	}
+
+	db.DB.Model(&recipe).Association("Tags").Find(&recipe.Tags)
+	utils.SuccessResponse(c, http.StatusOK, "Tag detached successfully", recipe.Tags)
+}
+
+func taggedRecipeIDs(tx *gorm.DB, tagID string) []string {
+	var recipeIDs []string
+	tx.Table("recipe_tags").Where("tag_id = ?", tagID).Pluck("recipe_id", &recipeIDs)
+	return recipeIDs
+}
+
+// reindexRecipes refreshes the search documents of recipes whose tags were
+// renamed or removed.
+func reindexRecipes(tx *gorm.DB, recipeIDs []string) error {
+	for _, id := range recipeIDs {
+		if err := models.ReindexRecipe(tx, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func bindTag(c *gin.Context, input models.TagInput) (models.Tag, bool) {
+	tag := models.Tag{
+		Name: strings.TrimSpace(input.Name),
+		Type: strings.ToLower(strings.TrimSpace(input.Type)),
+	}
+	if tag.Type == "" {
+		tag.Type = models.TagTypeGeneral
+	}
+	if !models.IsValidTagType(tag.Type) {
+		utils.ErrorResponse(c, http.StatusBadRequest,
+			"Invalid tag type. Use one of: "+strings.Join(models.TagTypes, ", "))
+		return tag, false
+	}
+	if utils.Slugify(tag.Name) == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Tag name must contain letters or digits")
+		return tag, false
+	}
+	return tag, true
+}
+
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	var out []string
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			out = append(out, v)
+		}
+	}
+	return out
+}
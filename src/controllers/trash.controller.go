@@ -0,0 +1,93 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"recipe-api/src/db"
+	"recipe-api/src/models"
+	"recipe-api/src/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// trashedRecipe is a soft-deleted recipe together with the time the purge
+// job will remove it for good.
+type trashedRecipe struct {
+	models.Recipe
+	PurgeAt time.Time `json:"purge_at"`
+}
+
+func newTrashedRecipe(recipe models.Recipe) trashedRecipe {
+	return trashedRecipe{
+		Recipe:  recipe,
+		PurgeAt: recipe.DeletedAt.Time.Add(utils.TrashRetention()),
+	}
+}
+
+// GetTrash lists the caller's deleted recipes that can still be restored,
+// most recently deleted first.
+func GetTrash(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "10"))
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 || perPage > 100 {
+		perPage = 10
+	}
+
+	query := db.DB.Unscoped().Model(&models.Recipe{}).
+		Where("user_id = ? AND deleted_at IS NOT NULL", currentUser(c).ID)
+
+	var totalCount int64
+	query.Count(&totalCount)
+
+	var recipes []models.Recipe
+	result := query.Order("deleted_at DESC, id DESC").
+		Limit(perPage).
+		Offset((page - 1) * perPage).
+		Find(&recipes)
+	if result.Error != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError,
+			"Failed to fetch trash: "+result.Error.Error())
+		return
+	}
+
+	trashed := make([]trashedRecipe, len(recipes))
+	for i, recipe := range recipes {
+		trashed[i] = newTrashedRecipe(recipe)
+	}
+
+	utils.PaginatedSuccessResponse(c, http.StatusOK,
+		"Trash fetched successfully", trashed, page, perPage, totalCount)
+}
+
+// RestoreRecipe takes a recipe back out of the trash. Its ratings,
+// ingredients, steps and revisions were kept, so it comes back unchanged.
+func RestoreRecipe(c *gin.Context) {
+	id := c.Param("id")
+	var recipe models.Recipe
+
+	err := db.DB.Unscoped().Scopes(readableRecipes(currentUser(c))).Where("deleted_at IS NOT NULL").
+		First(&recipe, "id = ?", id).Error
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "Recipe not found in trash")
+		return
+	}
+
+	if !canModifyRecipe(currentUser(c), &recipe, models.PermRecipesDeleteAny) {
+		utils.ErrorResponse(c, http.StatusForbidden, "You can only restore your own recipes")
+		return
+	}
+
+	if err := db.DB.Unscoped().Model(&recipe).Update("deleted_at", nil).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError,
+			"Failed to restore recipe: "+err.Error())
+		return
+	}
+
+	db.DB.Preload("IngredientItems", orderedIngredients).First(&recipe, "id = ?", id)
+	utils.SuccessResponse(c, http.StatusOK, "Recipe restored successfully ♻️", recipe)
+}
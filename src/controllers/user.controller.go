@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"net/http"
+	"strconv"
 
 	"recipe-api/src/db"
 	"recipe-api/src/models"
@@ -15,7 +16,7 @@ func RegisterUser(c *gin.Context) {
 
 	if err := c.ShouldBindJSON(&user); err != nil {
 		utils.ErrorResponse(c, http.StatusBadRequest,
-			"Invalid user data. Username and email are required: "+err.Error())
+			"Invalid user data. Username, email and a password of at least 8 characters are required: "+err.Error())
 		return
 	}
 
@@ -26,6 +27,18 @@ func RegisterUser(c *gin.Context) {
 		return
 	}
 
+	hash, err := utils.HashPassword(user.Password)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError,
+			"Failed to register user: "+err.Error())
+		return
+	}
+	user.PasswordHash = hash
+	user.Password = ""
+	user.Role = models.RoleUser
+	user.Banned = false
+	user.BannedAt = nil
+
 	result := db.DB.Create(&user)
 	if result.Error != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError,
@@ -40,7 +53,7 @@ func GetUserByID(c *gin.Context) {
 	id := c.Param("id")
 	var user models.User
 
-	result := db.DB.Preload("Recipes").First(&user, "id = ?", id)
+	result := db.DB.Preload("Recipes", listedRecipes(currentUser(c))).First(&user, "id = ?", id)
 	if result.Error != nil {
 		utils.ErrorResponse(c, http.StatusNotFound, "User not found")
 		return
@@ -48,3 +61,32 @@ func GetUserByID(c *gin.Context) {
 
 	utils.SuccessResponse(c, http.StatusOK, "User fetched successfully", user)
 }
+
+// GetUserRecipes lists a user's recipes with cursor pagination. It accepts
+// the same sort and filter parameters as the recipe list.
+func GetUserRecipes(c *gin.Context) {
+	var user models.User
+	if err := db.DB.First(&user, "id = ?", c.Param("id")).Error; err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "User not found")
+		return
+	}
+
+	perPage, _ := strconv.Atoi(c.DefaultQuery("per_page", "10"))
+	if perPage < 1 || perPage > 100 {
+		perPage = 10
+	}
+
+	sort := c.DefaultQuery("sort", "newest")
+	if _, ok := recipeSortOrders[sort]; !ok {
+		utils.ErrorResponse(c, http.StatusBadRequest,
+			"Invalid sort. Use one of: newest, rating, top, total_time, title")
+		return
+	}
+
+	query, ok := filterRecipes(c, db.DB.Model(&models.Recipe{}).Where("recipes.user_id = ?", user.ID))
+	if !ok {
+		return
+	}
+
+	respondRecipeCursorPage(c, query, sort, c.Query("cursor"), perPage)
+}
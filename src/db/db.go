@@ -3,6 +3,7 @@ package db
 import (
 	"log"
 	"os"
+	"strings"
 
 	"recipe-api/src/models"
 
@@ -20,7 +21,7 @@ func ConnectDatabase() {
 	}
 
 	var err error
-	DB, err = gorm.Open(sqlite.Open(dbPath), &gorm.Config{
+	DB, err = gorm.Open(sqlite.Open(sqliteDSN(dbPath)), &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Info),
 	})
 	if err != nil {
@@ -29,14 +30,83 @@ func ConnectDatabase() {
 
 	log.Println("✅ Database connected successfully (SQLite)")
 
+	ratingsMigrated := migrateLegacyRatings()
+	hasRatingStats := DB.Migrator().HasColumn(&models.Recipe{}, "rating_count")
+	hasLabels := DB.Migrator().HasColumn(&models.Recipe{}, "labels")
+	hasSearchNames := DB.Migrator().HasColumn(&models.RecipeIngredient{}, "search_name")
+
 	err = DB.AutoMigrate(
 		&models.User{},
 		&models.Recipe{},
 		&models.Rating{},
+		&models.APIKey{},
+		&models.RecipeIngredient{},
+		&models.RecipeStep{},
+		&models.Tag{},
+		&models.RecipeRevision{},
+		&models.Collection{},
+		&models.CollectionItem{},
 	)
 	if err != nil {
 		log.Fatalf("❌ Auto-migration failed: %v", err)
 	}
 
+	if ratingsMigrated || !hasRatingStats {
+		recomputeRatingStats()
+	}
+	refreshWeightedRatings()
+	repairRecipeVisibility()
+	migrateLegacyIngredients()
+	if !hasLabels {
+		backfillDietaryLabels()
+	}
+	if !hasSearchNames {
+		backfillIngredientSearchNames()
+	}
+	setupSearchIndex()
+
 	log.Println("✅ Database tables migrated successfully")
+
+	promoteAdmins()
+	reportPasswordlessUsers()
+}
+
+// sqliteDSN opens every transaction with BEGIN IMMEDIATE and waits up to
+// five seconds for a busy database. Deferred transactions that read before
+// writing would otherwise fail with "database is locked" when two of them
+// try to upgrade their lock at the same time.
+func sqliteDSN(path string) string {
+	separator := "?"
+	if strings.Contains(path, "?") {
+		separator = "&"
+	}
+	return path + separator + "_txlock=immediate&_busy_timeout=5000"
+}
+
+// promoteAdmins grants the admin role to the already-registered usernames
+// listed in ADMIN_USERNAMES, so the first admin can be bootstrapped.
+func promoteAdmins() {
+	list := os.Getenv("ADMIN_USERNAMES")
+	if list == "" {
+		return
+	}
+
+	var usernames []string
+	for _, name := range strings.Split(list, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			usernames = append(usernames, name)
+		}
+	}
+	if len(usernames) == 0 {
+		return
+	}
+
+	result := DB.Model(&models.User{}).
+		Where("username IN ?", usernames).
+		Update("role", models.RoleAdmin)
+	if result.Error != nil {
+		log.Printf("⚠️  Could not promote admins: %v", result.Error)
+		return
+	}
+	log.Printf("✅ Promoted %d user(s) to admin", result.RowsAffected)
 }
@@ -0,0 +1,37 @@
+package db
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"recipe-api/src/utils"
+)
+
+// StartBackgroundJobs launches the periodic maintenance jobs. Call it once
+// after ConnectDatabase.
+func StartBackgroundJobs() {
+	startJob("Scheduled publishing", "PUBLISH_INTERVAL_SECONDS", time.Minute, publishDueRecipes)
+	startJob("Trash purge", "TRASH_PURGE_INTERVAL_SECONDS", time.Hour, purgeTrash)
+}
+
+// startJob runs fn in the background right away and then on every tick.
+// envVar may override the interval, in seconds.
+func startJob(name, envVar string, interval time.Duration, fn func()) {
+	if v := os.Getenv(envVar); v != "" {
+		if parsed, err := strconv.Atoi(v); err == nil && parsed > 0 {
+			interval = time.Duration(parsed) * time.Second
+		}
+	}
+
+	utils.RunAsync(func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			fn()
+			<-ticker.C
+		}
+	})
+	log.Printf("✅ %s runs every %s", name, interval)
+}
@@ -0,0 +1,208 @@
+package db
+
+import (
+	"encoding/json"
+	"log"
+	"strings"
+
+	"recipe-api/src/models"
+	"recipe-api/src/utils"
+)
+
+// migrateLegacyRatings converts the old free-text ratings.user_name column
+// into a user_id reference. Names that match a registered username are
+// linked; duplicates per (recipe, user) keep only the most recent rating so
+// the unique index can be created. Ratings from unknown names are kept
+// without a user. It reports whether anything was migrated so averages can
+// be recomputed once the schema is up to date.
+func migrateLegacyRatings() bool {
+	migrator := DB.Migrator()
+	if !migrator.HasTable(&models.Rating{}) || !migrator.HasColumn(&models.Rating{}, "user_name") {
+		return false
+	}
+
+	log.Println("🔁 Migrating legacy ratings to user references...")
+
+	if !migrator.HasColumn(&models.Rating{}, "user_id") {
+		if err := DB.Exec("ALTER TABLE ratings ADD COLUMN user_id text").Error; err != nil {
+			log.Fatalf("❌ Rating migration failed: %v", err)
+		}
+	}
+
+	steps := []string{
+		`UPDATE ratings SET user_id = (SELECT users.id FROM users WHERE users.username = ratings.user_name)
+		 WHERE user_id IS NULL OR user_id = ''`,
+		`DELETE FROM ratings WHERE user_id IS NOT NULL AND id NOT IN (
+			SELECT id FROM (
+				SELECT id, ROW_NUMBER() OVER (
+					PARTITION BY recipe_id, user_id ORDER BY updated_at DESC, created_at DESC
+				) AS rn FROM ratings WHERE user_id IS NOT NULL
+			) WHERE rn = 1
+		 )`,
+	}
+	for _, step := range steps {
+		if err := DB.Exec(step).Error; err != nil {
+			log.Fatalf("❌ Rating migration failed: %v", err)
+		}
+	}
+
+	if err := migrator.DropColumn(&models.Rating{}, "user_name"); err != nil {
+		log.Fatalf("❌ Rating migration failed: %v", err)
+	}
+
+	log.Println("✅ Legacy ratings migrated")
+	return true
+}
+
+// recomputeRatingStats rebuilds the denormalized average, count and histogram
+// on every recipe from its visible ratings.
+func recomputeRatingStats() {
+	err := DB.Exec(`UPDATE recipes SET
+		average_rating = COALESCE((
+			SELECT AVG(score) FROM ratings WHERE ratings.recipe_id = recipes.id AND ratings.hidden = false
+		), 0),
+		rating_count = (
+			SELECT COUNT(*) FROM ratings WHERE ratings.recipe_id = recipes.id AND ratings.hidden = false
+		),
+		rating_histogram = (
+			SELECT '[' || COALESCE(SUM(score = 1), 0) || ',' || COALESCE(SUM(score = 2), 0) || ',' ||
+				COALESCE(SUM(score = 3), 0) || ',' || COALESCE(SUM(score = 4), 0) || ',' ||
+				COALESCE(SUM(score = 5), 0) || ']'
+			FROM ratings WHERE ratings.recipe_id = recipes.id AND ratings.hidden = false
+		)`).Error
+	if err != nil {
+		log.Fatalf("❌ Failed to recompute rating stats: %v", err)
+	}
+}
+
+// refreshWeightedRatings re-derives every recipe's Bayesian score from its
+// stored average and count, so changes to the configured prior take effect
+// on startup.
+func refreshWeightedRatings() {
+	mean, weight := utils.RatingPrior()
+	err := DB.Exec(`UPDATE recipes SET weighted_rating = CASE
+		WHEN ? + rating_count = 0 THEN 0
+		ELSE (? * ? + average_rating * rating_count) / (? + rating_count)
+	END`, weight, weight, mean, weight).Error
+	if err != nil {
+		log.Fatalf("❌ Failed to refresh weighted ratings: %v", err)
+	}
+}
+
+// migrateLegacyIngredients creates structured ingredient rows for recipes
+// that only have the legacy JSON array of strings, parsing each line into
+// quantity, unit, name and note.
+func migrateLegacyIngredients() {
+	var recipes []models.Recipe
+	err := DB.Where("ingredients <> '' AND id NOT IN (?)",
+		DB.Model(&models.RecipeIngredient{}).Distinct("recipe_id")).
+		Find(&recipes).Error
+	if err != nil {
+		log.Fatalf("❌ Ingredient migration failed: %v", err)
+	}
+
+	migrated := 0
+	for _, recipe := range recipes {
+		var lines []string
+		if err := json.Unmarshal([]byte(recipe.Ingredients), &lines); err != nil {
+			log.Printf("⚠️  Skipping recipe %s: ingredients are not a JSON array", recipe.ID)
+			continue
+		}
+
+		var items []models.RecipeIngredient
+		for _, line := range lines {
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			item := models.IngredientFromLine(line)
+			item.RecipeID = recipe.ID
+			item.Position = len(items)
+			items = append(items, item)
+		}
+		if len(items) == 0 {
+			continue
+		}
+
+		if err := DB.Create(&items).Error; err != nil {
+			log.Fatalf("❌ Ingredient migration failed for recipe %s: %v", recipe.ID, err)
+		}
+		migrated++
+	}
+
+	if migrated > 0 {
+		log.Printf("✅ Migrated ingredients for %d legacy recipe(s)", migrated)
+	}
+}
+
+// backfillDietaryLabels computes labels for every recipe from its structured
+// ingredients. It runs once, when the labels column is first added.
+func backfillDietaryLabels() {
+	var recipes []models.Recipe
+	if err := DB.Preload("IngredientItems").Find(&recipes).Error; err != nil {
+		log.Fatalf("❌ Dietary label backfill failed: %v", err)
+	}
+
+	for _, recipe := range recipes {
+		names := make([]string, len(recipe.IngredientItems))
+		for i, item := range recipe.IngredientItems {
+			names[i] = item.Name
+		}
+		labels := models.StringList(utils.DietaryLabels(names))
+		if err := DB.Model(&models.Recipe{}).Where("id = ?", recipe.ID).
+			UpdateColumn("labels", labels).Error; err != nil {
+			log.Fatalf("❌ Dietary label backfill failed for recipe %s: %v", recipe.ID, err)
+		}
+	}
+
+	log.Printf("✅ Computed dietary labels for %d recipe(s)", len(recipes))
+}
+
+// backfillIngredientSearchNames fills search_name, the stemmed and
+// synonym-normalized ingredient name used by ingredient search, for rows
+// created before the column existed.
+func backfillIngredientSearchNames() {
+	var items []models.RecipeIngredient
+	if err := DB.Select("id", "name").Find(&items).Error; err != nil {
+		log.Fatalf("❌ Ingredient search name backfill failed: %v", err)
+	}
+
+	for _, item := range items {
+		if err := DB.Model(&models.RecipeIngredient{}).Where("id = ?", item.ID).
+			UpdateColumn("search_name", utils.NormalizeIngredientName(item.Name)).Error; err != nil {
+			log.Fatalf("❌ Ingredient search name backfill failed for %s: %v", item.ID, err)
+		}
+	}
+
+	log.Printf("✅ Normalized %d ingredient name(s) for search", len(items))
+}
+
+// repairRecipeVisibility makes recipes private when their visibility is not
+// one of models.Visibilities. Older builds let raw updates store any string,
+// which no visibility check matched.
+func repairRecipeVisibility() {
+	result := DB.Exec("UPDATE recipes SET visibility = ? WHERE visibility NOT IN ?",
+		models.VisibilityPrivate, models.Visibilities)
+	if result.Error != nil {
+		log.Fatalf("❌ Failed to repair recipe visibility: %v", result.Error)
+	}
+	if result.RowsAffected > 0 {
+		log.Printf("⚠️  Made %d recipe(s) with an invalid visibility private", result.RowsAffected)
+	}
+}
+
+// reportPasswordlessUsers lists accounts created before passwords were
+// hashed. They cannot log in until an admin issues them a reset token via
+// POST /api/admin/users/:id/password-reset.
+func reportPasswordlessUsers() {
+	var usernames []string
+	if err := DB.Model(&models.User{}).
+		Where("password_hash IS NULL OR password_hash = ''").
+		Pluck("username", &usernames).Error; err != nil {
+		log.Printf("⚠️  Could not check for users without a password: %v", err)
+		return
+	}
+	if len(usernames) > 0 {
+		log.Printf("⚠️  %d user(s) have no password and need a reset token to log in: %s",
+			len(usernames), strings.Join(usernames, ", "))
+	}
+}
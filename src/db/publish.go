@@ -0,0 +1,28 @@
+package db
+
+import (
+	"log"
+	"time"
+
+	"recipe-api/src/models"
+)
+
+// publishDueRecipes makes scheduled recipes public and clears their
+// publish_at. publish_at is stored in UTC, so it is compared against the
+// current UTC time.
+func publishDueRecipes() {
+	result := DB.Model(&models.Recipe{}).
+		Where("visibility <> ? AND publish_at IS NOT NULL AND publish_at <= ?",
+			models.VisibilityPublic, time.Now().UTC()).
+		Updates(map[string]interface{}{
+			"visibility": models.VisibilityPublic,
+			"publish_at": nil,
+		})
+	if result.Error != nil {
+		log.Printf("⚠️  Scheduled publishing failed: %v", result.Error)
+		return
+	}
+	if result.RowsAffected > 0 {
+		log.Printf("✅ Published %d scheduled recipe(s)", result.RowsAffected)
+	}
+}
@@ -0,0 +1,42 @@
+package db
+
+import (
+	"log"
+
+	"recipe-api/src/models"
+)
+
+// setupSearchIndex creates the FTS5 table behind full-text search and
+// rebuilds it when it is out of step with the recipes table, e.g. on first
+// start or after recipes were written by a build without FTS5. The
+// mattn/go-sqlite3 driver only ships FTS5 when built with -tags sqlite_fts5;
+// without it search is disabled and everything else keeps working.
+func setupSearchIndex() {
+	err := DB.Exec(`CREATE VIRTUAL TABLE IF NOT EXISTS ` + models.RecipeSearchTable + ` USING fts5(
+		recipe_id UNINDEXED, title, description, ingredients, tags,
+		tokenize = 'porter unicode61', prefix = '2 3'
+	)`).Error
+
+	// An existing table is not checked on CREATE ... IF NOT EXISTS, so
+	// reading from it is what proves the module is available.
+	var indexed, recipes int64
+	if err == nil {
+		err = DB.Table(models.RecipeSearchTable).Count(&indexed).Error
+	}
+	if err != nil {
+		log.Printf("⚠️  Full-text search disabled (build with -tags sqlite_fts5): %v", err)
+		return
+	}
+	models.EnableSearchIndex()
+
+	DB.Model(&models.Recipe{}).Count(&recipes)
+	if indexed == recipes {
+		return
+	}
+
+	log.Println("🔁 Rebuilding recipe search index...")
+	if err := models.RebuildSearchIndex(DB); err != nil {
+		log.Fatalf("❌ Search index rebuild failed: %v", err)
+	}
+	log.Printf("✅ Indexed %d recipe(s) for full-text search", recipes)
+}
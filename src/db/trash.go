@@ -0,0 +1,74 @@
+package db
+
+import (
+	"log"
+	"time"
+
+	"recipe-api/src/models"
+	"recipe-api/src/utils"
+
+	"gorm.io/gorm"
+)
+
+// purgeTrash permanently deletes recipes that have been in the trash longer
+// than utils.TrashRetention, along with their ratings, ingredients, steps,
+// revisions, tag links, collection entries and image file. Each recipe is
+// purged in its own transaction so one failure does not hold back the rest.
+func purgeTrash() {
+	var recipes []models.Recipe
+	err := DB.Unscoped().
+		Where("deleted_at IS NOT NULL AND deleted_at <= ?", time.Now().Add(-utils.TrashRetention())).
+		Find(&recipes).Error
+	if err != nil {
+		log.Printf("⚠️  Trash purge failed: %v", err)
+		return
+	}
+
+	purged := 0
+	for i := range recipes {
+		recipe := &recipes[i]
+		err := DB.Transaction(func(tx *gorm.DB) error {
+			for _, child := range []interface{}{
+				&models.Rating{}, &models.RecipeIngredient{}, &models.RecipeStep{}, &models.RecipeRevision{},
+				&models.CollectionItem{},
+			} {
+				if err := tx.Where("recipe_id = ?", recipe.ID).Delete(child).Error; err != nil {
+					return err
+				}
+			}
+			if err := tx.Exec("DELETE FROM recipe_tags WHERE recipe_id = ?", recipe.ID).Error; err != nil {
+				return err
+			}
+			return tx.Unscoped().Delete(recipe).Error
+		})
+		if err != nil {
+			log.Printf("⚠️  Could not purge recipe %s: %v", recipe.ID, err)
+			continue
+		}
+		removeUnusedImage(recipe)
+		purged++
+	}
+
+	if purged > 0 {
+		log.Printf("🗑️  Purged %d recipe(s) from the trash", purged)
+	}
+}
+
+// removeUnusedImage deletes the image file of a purged recipe unless another
+// recipe, trashed or not, still points at it.
+func removeUnusedImage(recipe *models.Recipe) {
+	if recipe.ImageURL == "" {
+		return
+	}
+	var users int64
+	if err := DB.Unscoped().Model(&models.Recipe{}).Where("image_url = ?", recipe.ImageURL).Count(&users).Error; err != nil {
+		log.Printf("⚠️  Could not check image of purged recipe %s: %v", recipe.ID, err)
+		return
+	}
+	if users > 0 {
+		return
+	}
+	if err := utils.RemoveImage(recipe.ImageURL); err != nil {
+		log.Printf("⚠️  Could not remove image of purged recipe %s: %v", recipe.ID, err)
+	}
+}
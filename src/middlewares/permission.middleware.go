@@ -0,0 +1,43 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"recipe-api/src/models"
+	"recipe-api/src/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequirePermission must run after RequireAuth. It lets the request through
+// only when the current user's role grants every listed permission. Role
+// permissions are never delegated to API keys.
+func RequirePermission(permissions ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, exists := c.Get("currentUser")
+		user, ok := value.(*models.User)
+		if !exists || !ok {
+			utils.ErrorResponse(c, http.StatusUnauthorized, "Authentication required")
+			c.Abort()
+			return
+		}
+
+		if _, usingKey := c.Get("apiKey"); usingKey {
+			utils.ErrorResponse(c, http.StatusForbidden,
+				"This action requires a user session, not an API key")
+			c.Abort()
+			return
+		}
+
+		for _, permission := range permissions {
+			if !user.Can(permission) {
+				utils.ErrorResponse(c, http.StatusForbidden,
+					"You do not have permission to perform this action")
+				c.Abort()
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	ScopeRecipesRead  = "recipes:read"
+	ScopeRecipesWrite = "recipes:write"
+	ScopeRatingsRead  = "ratings:read"
+	ScopeRatingsWrite = "ratings:write"
+)
+
+var ValidScopes = map[string]bool{
+	ScopeRecipesRead:  true,
+	ScopeRecipesWrite: true,
+	ScopeRatingsRead:  true,
+	ScopeRatingsWrite: true,
+}
+
+type APIKey struct {
+	ID         string     `gorm:"type:text;primaryKey" json:"id"`
+	UserID     string     `gorm:"type:text;index;not null" json:"user_id"`
+	Name       string     `gorm:"type:text;not null" json:"name"`
+	Prefix     string     `gorm:"type:text;not null" json:"prefix"`
+	KeyHash    string     `gorm:"type:text;uniqueIndex;not null" json:"-"`
+	Scopes     string     `gorm:"type:text;not null" json:"scopes"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	User       *User      `gorm:"foreignKey:UserID" json:"-"`
+}
+
+type CreateAPIKeyInput struct {
+	Name   string   `json:"name" binding:"required"`
+	Scopes []string `json:"scopes" binding:"required,min=1"`
+}
+
+func (k *APIKey) BeforeCreate(tx *gorm.DB) error {
+	if k.ID == "" {
+		k.ID = uuid.New().String()
+	}
+	return nil
+}
+
+func (k *APIKey) HasScope(scope string) bool {
+	for _, s := range strings.Split(k.Scopes, ",") {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Collection is a user-curated cookbook: an ordered list of recipes, each
+// with an optional note. Private collections are only visible to their owner.
+type Collection struct {
+	ID          string           `gorm:"type:text;primaryKey" json:"id"`
+	UserID      string           `gorm:"type:text;index;not null" json:"user_id"`
+	Name        string           `gorm:"type:text;not null" json:"name"`
+	Description string           `gorm:"type:text" json:"description"`
+	Visibility  string           `gorm:"type:text;not null;default:private" json:"visibility"`
+	RecipeCount int              `gorm:"-" json:"recipe_count"`
+	CreatedAt   time.Time        `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time        `gorm:"autoUpdateTime" json:"updated_at"`
+	Items       []CollectionItem `gorm:"foreignKey:CollectionID" json:"items,omitempty"`
+}
+
+type CollectionItem struct {
+	ID           string    `gorm:"type:text;primaryKey" json:"id"`
+	CollectionID string    `gorm:"type:text;not null;uniqueIndex:idx_collection_items_recipe" json:"collection_id"`
+	RecipeID     string    `gorm:"type:text;not null;uniqueIndex:idx_collection_items_recipe;index" json:"recipe_id"`
+	Position     int       `gorm:"not null;default:0" json:"position"`
+	Note         string    `gorm:"type:text" json:"note"`
+	CreatedAt    time.Time `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt    time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+	Recipe       *Recipe   `gorm:"foreignKey:RecipeID" json:"recipe,omitempty"`
+}
+
+type CollectionInput struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+	Visibility  string `json:"visibility"`
+}
+
+type CollectionRecipeInput struct {
+	RecipeID string `json:"recipe_id" binding:"required"`
+	Note     string `json:"note"`
+	Position *int   `json:"position" binding:"omitempty,min=0"`
+}
+
+type CollectionNoteInput struct {
+	Note string `json:"note"`
+}
+
+type ReorderCollectionInput struct {
+	RecipeIDs []string `json:"recipe_ids" binding:"required,min=1"`
+}
+
+// IsValidCollectionVisibility reports whether visibility is one a collection
+// can have. Collections are either shared or kept to their owner.
+func IsValidCollectionVisibility(visibility string) bool {
+	return visibility == VisibilityPublic || visibility == VisibilityPrivate
+}
+
+func (c *Collection) BeforeCreate(tx *gorm.DB) error {
+	if c.ID == "" {
+		c.ID = uuid.New().String()
+	}
+	if c.Visibility == "" {
+		c.Visibility = VisibilityPrivate
+	}
+	return nil
+}
+
+func (i *CollectionItem) BeforeCreate(tx *gorm.DB) error {
+	if i.ID == "" {
+		i.ID = uuid.New().String()
+	}
+	return nil
+}
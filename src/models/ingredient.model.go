@@ -0,0 +1,83 @@
+package models
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"recipe-api/src/utils"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type RecipeIngredient struct {
+	ID            string   `gorm:"type:text;primaryKey" json:"id"`
+	RecipeID      string   `gorm:"type:text;index;not null" json:"recipe_id"`
+	Position      int      `gorm:"not null;default:0" json:"position"`
+	Quantity      *float64 `json:"quantity"`
+	QuantityMax   *float64 `json:"quantity_max,omitempty"`
+	Unit          string   `gorm:"type:text" json:"unit"`
+	Name          string   `gorm:"type:text;not null" json:"name"`
+	CanonicalName string   `gorm:"type:text;index;not null" json:"canonical_name"`
+	SearchName    string   `gorm:"type:text;index" json:"-"`
+	Note          string   `gorm:"type:text" json:"note"`
+}
+
+type IngredientInput struct {
+	Quantity    *float64 `json:"quantity"`
+	QuantityMax *float64 `json:"quantity_max"`
+	Unit        string   `json:"unit"`
+	Name        string   `json:"name"`
+	Note        string   `json:"note"`
+}
+
+// IngredientFromLine parses a free-text line like "3 large eggs" into an
+// ingredient row.
+func IngredientFromLine(line string) RecipeIngredient {
+	parsed := utils.ParseIngredientLine(line)
+	return RecipeIngredient{
+		Quantity:    parsed.Quantity,
+		QuantityMax: parsed.QuantityMax,
+		Unit:        parsed.Unit,
+		Name:        parsed.Name,
+		Note:        parsed.Note,
+	}
+}
+
+func (i *RecipeIngredient) BeforeSave(tx *gorm.DB) error {
+	if i.ID == "" {
+		i.ID = uuid.New().String()
+	}
+	i.Name = strings.TrimSpace(i.Name)
+	i.CanonicalName = utils.CanonicalIngredientName(i.Name)
+	i.SearchName = utils.NormalizeIngredientName(i.Name)
+	return nil
+}
+
+// String renders the ingredient the way a cook would write it, e.g.
+// "2 cup flour, sifted". It is used for the legacy ingredients column.
+func (i RecipeIngredient) String() string {
+	var parts []string
+	if i.Quantity != nil {
+		quantity := formatQuantity(*i.Quantity)
+		if i.QuantityMax != nil {
+			quantity += "-" + formatQuantity(*i.QuantityMax)
+		}
+		parts = append(parts, quantity)
+	}
+	if i.Unit != "" {
+		parts = append(parts, i.Unit)
+	}
+	parts = append(parts, i.Name)
+
+	line := strings.Join(parts, " ")
+	if i.Note != "" {
+		line += ", " + i.Note
+	}
+	return line
+}
+
+func formatQuantity(q float64) string {
+	return strconv.FormatFloat(math.Round(q*1000)/1000, 'f', -1, 64)
+}
@@ -8,13 +8,20 @@ import (
 )
 
 type Rating struct {
-	ID        string    `gorm:"type:text;primaryKey" json:"id"`
-	RecipeID  string    `gorm:"type:text;index;not null" json:"recipe_id"`
-	UserName  string    `gorm:"type:text;not null" json:"user_name" binding:"required"`
-	Score     int       `gorm:"not null" json:"score" binding:"required,min=1,max=5"`
-	Comment   string    `gorm:"type:text" json:"comment"`
-	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+	ID        string      `gorm:"type:text;primaryKey" json:"id"`
+	RecipeID  string      `gorm:"type:text;index;not null;uniqueIndex:idx_ratings_recipe_user" json:"recipe_id"`
+	UserID    string      `gorm:"type:text;index;uniqueIndex:idx_ratings_recipe_user" json:"user_id"`
+	Score     int         `gorm:"not null" json:"score" binding:"required,min=1,max=5"`
+	Comment   string      `gorm:"type:text" json:"comment"`
+	Hidden    bool        `gorm:"default:false;index" json:"hidden"`
+	CreatedAt time.Time   `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time   `gorm:"autoUpdateTime" json:"updated_at"`
+	User      *PublicUser `gorm:"foreignKey:UserID" json:"user,omitempty"`
+}
+
+type RatingInput struct {
+	Score   int    `json:"score" binding:"required,min=1,max=5"`
+	Comment string `json:"comment"`
 }
 
 func (r *Rating) BeforeCreate(tx *gorm.DB) error {
@@ -1,31 +1,164 @@
 package models
 
 import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
 	"time"
 
+	"recipe-api/src/utils"
+
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+const (
+	VisibilityDraft    = "draft"
+	VisibilityPrivate  = "private"
+	VisibilityUnlisted = "unlisted"
+	VisibilityPublic   = "public"
+)
+
+var Visibilities = []string{VisibilityDraft, VisibilityPrivate, VisibilityUnlisted, VisibilityPublic}
+
+var ErrInvalidVisibility = errors.New("invalid recipe visibility")
+
+func IsValidVisibility(visibility string) bool {
+	for _, v := range Visibilities {
+		if v == visibility {
+			return true
+		}
+	}
+	return false
+}
+
 type Recipe struct {
-	ID            string    `gorm:"type:text;primaryKey" json:"id"`
-	Title         string    `gorm:"type:text;not null" json:"title" binding:"required"`
-	Description   string    `gorm:"type:text" json:"description"`
-	ImageURL      string    `gorm:"type:text" json:"image_url"`
-	Ingredients   string    `gorm:"type:text" json:"ingredients" binding:"required"`
-	PrepTime      int       `gorm:"default:0" json:"prep_time"`
-	CookTime      int       `gorm:"default:0" json:"cook_time"`
-	Servings      int       `gorm:"default:1" json:"servings"`
-	AverageRating float64   `gorm:"default:0" json:"average_rating"`
-	UserID        string    `gorm:"type:text;index" json:"user_id"`
-	CreatedAt     time.Time `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt     time.Time `gorm:"autoUpdateTime" json:"updated_at"`
-	Ratings       []Rating  `gorm:"foreignKey:RecipeID" json:"ratings,omitempty"`
+	ID              string                 `gorm:"type:text;primaryKey" json:"id"`
+	Title           string                 `gorm:"type:text;not null" json:"title" binding:"required"`
+	Description     string                 `gorm:"type:text" json:"description"`
+	ImageURL        string                 `gorm:"type:text" json:"image_url"`
+	Ingredients     string                 `gorm:"type:text" json:"ingredients" binding:"required"`
+	PrepTime        int                    `gorm:"default:0" json:"prep_time"`
+	CookTime        int                    `gorm:"default:0" json:"cook_time"`
+	Servings        int                    `gorm:"default:1" json:"servings"`
+	AverageRating   float64                `gorm:"default:0" json:"average_rating"`
+	RatingCount     int                    `gorm:"default:0" json:"rating_count"`
+	WeightedRating  float64                `gorm:"default:0;index" json:"weighted_rating"`
+	RatingHistogram RatingHistogram        `gorm:"type:text;default:'[0,0,0,0,0]'" json:"rating_histogram"`
+	Labels          StringList             `gorm:"type:text;default:'[]'" json:"labels"`
+	UserID          string                 `gorm:"type:text;index" json:"user_id"`
+	ForkedFromID    *string                `gorm:"type:text;index" json:"forked_from"`
+	Visibility      string                 `gorm:"type:text;not null;default:public;index" json:"visibility"`
+	PublishAt       *time.Time             `gorm:"index" json:"publish_at"`
+	CreatedAt       time.Time              `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt       time.Time              `gorm:"autoUpdateTime" json:"updated_at"`
+	DeletedAt       gorm.DeletedAt         `gorm:"index" json:"deleted_at"`
+	IngredientItems []RecipeIngredient     `gorm:"foreignKey:RecipeID" json:"ingredient_items,omitempty"`
+	Steps           []RecipeStep           `gorm:"foreignKey:RecipeID" json:"steps,omitempty"`
+	Tags            []Tag                  `gorm:"many2many:recipe_tags" json:"tags,omitempty"`
+	Ratings         []Rating               `gorm:"foreignKey:RecipeID" json:"ratings,omitempty"`
+	Nutrition       *utils.NutritionReport `gorm:"-" json:"nutrition,omitempty"`
+	Attribution     *RecipeAttribution     `gorm:"-" json:"attribution,omitempty"`
+}
+
+// RecipeAttribution credits the recipe a fork was copied from. Only
+// RecipeID is set when the original no longer exists.
+type RecipeAttribution struct {
+	RecipeID       string `json:"recipe_id"`
+	Title          string `json:"title,omitempty"`
+	AuthorID       string `json:"author_id,omitempty"`
+	AuthorUsername string `json:"author_username,omitempty"`
 }
 
 func (r *Recipe) BeforeCreate(tx *gorm.DB) error {
 	if r.ID == "" {
 		r.ID = uuid.New().String()
 	}
+	if r.Visibility == "" {
+		r.Visibility = VisibilityPublic
+	}
+	return nil
+}
+
+// BeforeSave refuses to store a visibility outside Visibilities, whether it
+// comes from the struct or from an Updates map. Handlers validate first; this
+// only stops a path that forgot to. An empty value on create is defaulted by
+// BeforeCreate.
+func (r *Recipe) BeforeSave(tx *gorm.DB) error {
+	visibility, set := r.Visibility, r.Visibility != ""
+	if updates, ok := tx.Statement.Dest.(map[string]interface{}); ok {
+		for _, key := range []string{"visibility", "Visibility"} {
+			if raw, ok := updates[key]; ok {
+				visibility, _ = raw.(string)
+				set = true
+			}
+		}
+	}
+	if set && !IsValidVisibility(visibility) {
+		return ErrInvalidVisibility
+	}
+	return nil
+}
+
+// IsReadable reports whether anyone, not just the owner, may open the
+// recipe. Unlisted recipes are readable by link but left out of lists.
+func (r *Recipe) IsReadable() bool {
+	return r.Visibility == VisibilityPublic || r.Visibility == VisibilityUnlisted
+}
+
+// The search index follows recipe writes made through GORM. Bulk updates
+// without a primary key (such as rating aggregation) are skipped.
+func (r *Recipe) AfterCreate(tx *gorm.DB) error {
+	return ReindexRecipe(tx, r.ID)
+}
+
+func (r *Recipe) AfterUpdate(tx *gorm.DB) error {
+	return ReindexRecipe(tx, r.ID)
+}
+
+func (r *Recipe) AfterDelete(tx *gorm.DB) error {
+	return ReindexRecipe(tx, r.ID)
+}
+
+// RatingHistogram holds the number of visible ratings per score, index 0 being
+// one star. It is stored as a JSON array and rendered as {"1": n, ..., "5": n}.
+type RatingHistogram [5]int
+
+func (h RatingHistogram) Value() (driver.Value, error) {
+	b, err := json.Marshal([5]int(h))
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (h *RatingHistogram) Scan(value interface{}) error {
+	var raw []byte
+	switch v := value.(type) {
+	case nil:
+		*h = RatingHistogram{}
+		return nil
+	case string:
+		raw = []byte(v)
+	case []byte:
+		raw = v
+	default:
+		return fmt.Errorf("unsupported rating histogram type %T", value)
+	}
+	var counts [5]int
+	if err := json.Unmarshal(raw, &counts); err != nil {
+		return err
+	}
+	*h = counts
 	return nil
 }
+
+func (h RatingHistogram) MarshalJSON() ([]byte, error) {
+	out := make(map[string]int, len(h))
+	for i, n := range h {
+		out[strconv.Itoa(i+1)] = n
+	}
+	return json.Marshal(out)
+}
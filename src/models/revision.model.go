@@ -0,0 +1,180 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	RevisionActionBaseline = "baseline"
+	RevisionActionCreate   = "create"
+	RevisionActionUpdate   = "update"
+	RevisionActionRevert   = "revert"
+)
+
+// RecipeRevision is an immutable snapshot of a recipe's editable content,
+// taken after every change. Number counts up from 1 per recipe.
+type RecipeRevision struct {
+	ID           string         `gorm:"type:text;primaryKey" json:"id"`
+	RecipeID     string         `gorm:"type:text;not null;uniqueIndex:idx_revisions_recipe_number" json:"recipe_id"`
+	Number       int            `gorm:"not null;uniqueIndex:idx_revisions_recipe_number" json:"number"`
+	UserID       string         `gorm:"type:text;index" json:"user_id"`
+	User         *PublicUser    `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Action       string         `gorm:"type:text;not null" json:"action"`
+	RevertedFrom *int           `json:"reverted_from,omitempty"`
+	Snapshot     RecipeSnapshot `gorm:"type:text;not null" json:"snapshot"`
+	CreatedAt    time.Time      `gorm:"autoCreateTime" json:"created_at"`
+}
+
+// RecipeSnapshot holds the fields a revision captures and a revert restores.
+type RecipeSnapshot struct {
+	Title       string            `json:"title"`
+	Description string            `json:"description"`
+	ImageURL    string            `json:"image_url"`
+	PrepTime    int               `json:"prep_time"`
+	CookTime    int               `json:"cook_time"`
+	Servings    int               `json:"servings"`
+	Ingredients []IngredientInput `json:"ingredients"`
+	// Steps and Tags are nil in revisions recorded before they were
+	// captured; reverting to such a revision leaves them as they are.
+	Steps []StepSnapshot `json:"steps"`
+	Tags  []TagSnapshot  `json:"tags"`
+}
+
+// StepSnapshot is a step as a revision captures it. Ingredients holds the
+// positions of the ingredient lines it references, since row IDs are not
+// part of a snapshot.
+type StepSnapshot struct {
+	Instruction     string `json:"instruction"`
+	DurationMinutes *int   `json:"duration_minutes"`
+	Ingredients     []int  `json:"ingredients"`
+}
+
+type TagSnapshot struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+func (r *RecipeRevision) BeforeCreate(tx *gorm.DB) error {
+	if r.ID == "" {
+		r.ID = uuid.New().String()
+	}
+	return nil
+}
+
+func (r *RecipeRevision) BeforeUpdate(tx *gorm.DB) error {
+	return errors.New("recipe revisions are immutable")
+}
+
+// SnapshotRecipe captures recipe, whose IngredientItems, Steps (in order) and
+// Tags must be loaded.
+func SnapshotRecipe(recipe *Recipe) RecipeSnapshot {
+	snapshot := RecipeSnapshot{
+		Title:       recipe.Title,
+		Description: recipe.Description,
+		ImageURL:    recipe.ImageURL,
+		PrepTime:    recipe.PrepTime,
+		CookTime:    recipe.CookTime,
+		Servings:    recipe.Servings,
+		Ingredients: make([]IngredientInput, len(recipe.IngredientItems)),
+		Steps:       make([]StepSnapshot, len(recipe.Steps)),
+		Tags:        make([]TagSnapshot, len(recipe.Tags)),
+	}
+	positions := make(map[string]int, len(recipe.IngredientItems))
+	for i, item := range recipe.IngredientItems {
+		snapshot.Ingredients[i] = IngredientInput{
+			Quantity:    item.Quantity,
+			QuantityMax: item.QuantityMax,
+			Unit:        item.Unit,
+			Name:        item.Name,
+			Note:        item.Note,
+		}
+		positions[item.ID] = i
+	}
+	for i, step := range recipe.Steps {
+		refs := []int{}
+		for _, id := range step.IngredientIDs {
+			if position, ok := positions[id]; ok {
+				refs = append(refs, position)
+			}
+		}
+		snapshot.Steps[i] = StepSnapshot{
+			Instruction:     step.Instruction,
+			DurationMinutes: step.DurationMinutes,
+			Ingredients:     refs,
+		}
+	}
+	for i, tag := range recipe.Tags {
+		snapshot.Tags[i] = TagSnapshot{ID: tag.ID, Name: tag.Name, Type: tag.Type}
+	}
+	sort.Slice(snapshot.Tags, func(i, j int) bool { return snapshot.Tags[i].Name < snapshot.Tags[j].Name })
+	return snapshot
+}
+
+// IngredientItems rebuilds ingredient rows from the snapshot, in order.
+func (s RecipeSnapshot) IngredientItems() []RecipeIngredient {
+	items := make([]RecipeIngredient, len(s.Ingredients))
+	for i, input := range s.Ingredients {
+		items[i] = RecipeIngredient{
+			Position:    i,
+			Quantity:    input.Quantity,
+			QuantityMax: input.QuantityMax,
+			Unit:        input.Unit,
+			Name:        input.Name,
+			Note:        input.Note,
+		}
+	}
+	return items
+}
+
+// StepItems rebuilds step rows from the snapshot, in order, pointing them at
+// ingredients, the recipe's restored ingredient rows.
+func (s RecipeSnapshot) StepItems(recipeID string, ingredients []RecipeIngredient) []RecipeStep {
+	steps := make([]RecipeStep, len(s.Steps))
+	for i, step := range s.Steps {
+		refs := StringList{}
+		for _, position := range step.Ingredients {
+			if position >= 0 && position < len(ingredients) {
+				refs = append(refs, ingredients[position].ID)
+			}
+		}
+		steps[i] = RecipeStep{
+			RecipeID:        recipeID,
+			Position:        i,
+			Instruction:     step.Instruction,
+			DurationMinutes: step.DurationMinutes,
+			IngredientIDs:   refs,
+		}
+	}
+	return steps
+}
+
+func (s RecipeSnapshot) Value() (driver.Value, error) {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (s *RecipeSnapshot) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*s = RecipeSnapshot{}
+		return nil
+	case string:
+		return json.Unmarshal([]byte(v), s)
+	case []byte:
+		return json.Unmarshal(v, s)
+	default:
+		return fmt.Errorf("unsupported recipe snapshot type %T", value)
+	}
+}
@@ -0,0 +1,54 @@
+package models
+
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
+const (
+	PermRecipesUpdateAny   = "recipes:update:any"
+	PermRecipesDeleteAny   = "recipes:delete:any"
+	PermRatingsDeleteAny   = "ratings:delete:any"
+	PermRatingsModerate    = "ratings:moderate"
+	PermUsersBan           = "users:ban"
+	PermUsersManageRoles   = "users:manage_roles"
+	PermUsersResetPassword = "users:reset_password"
+	PermStatsRead          = "stats:read"
+	PermTagsManage         = "tags:manage"
+)
+
+// RolePermissions is the single source of truth for what each role may do.
+// Plain users have no extra permissions beyond acting on their own content.
+var RolePermissions = map[string][]string{
+	RoleUser: {},
+	RoleModerator: {
+		PermRatingsModerate,
+		PermTagsManage,
+	},
+	RoleAdmin: {
+		PermRecipesUpdateAny,
+		PermRecipesDeleteAny,
+		PermRatingsDeleteAny,
+		PermRatingsModerate,
+		PermUsersBan,
+		PermUsersManageRoles,
+		PermUsersResetPassword,
+		PermStatsRead,
+		PermTagsManage,
+	},
+}
+
+func IsValidRole(role string) bool {
+	_, ok := RolePermissions[role]
+	return ok
+}
+
+func HasPermission(role, permission string) bool {
+	for _, p := range RolePermissions[role] {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+package models
+
+import "gorm.io/gorm"
+
+// RecipeSearchTable is the FTS5 virtual table mirroring each recipe's title,
+// description, ingredient names and tag names. Its recipe_id column is not
+// indexed for matching; it only links rows back to recipes.
+const RecipeSearchTable = "recipes_fts"
+
+// searchIndexEnabled is switched on once the FTS5 table exists. SQLite builds
+// without FTS5 leave it off and the recipe hooks skip indexing.
+var searchIndexEnabled bool
+
+func EnableSearchIndex() {
+	searchIndexEnabled = true
+}
+
+func SearchIndexEnabled() bool {
+	return searchIndexEnabled
+}
+
+const searchDocumentSelect = `SELECT r.id, r.title, r.description,
+	COALESCE((SELECT group_concat(ri.name, ' ') FROM recipe_ingredients ri WHERE ri.recipe_id = r.id), ''),
+	COALESCE((SELECT group_concat(t.name, ' ') FROM tags t
+		JOIN recipe_tags rt ON rt.tag_id = t.id WHERE rt.recipe_id = r.id), '')
+	FROM recipes r WHERE r.deleted_at IS NULL`
+
+// ReindexRecipe refreshes the search document of one recipe, removing it
+// when the recipe no longer exists or is in the trash. Call it inside the
+// transaction that changed the recipe's ingredients or tags.
+func ReindexRecipe(tx *gorm.DB, recipeID string) error {
+	if !searchIndexEnabled || recipeID == "" {
+		return nil
+	}
+	if err := tx.Exec("DELETE FROM "+RecipeSearchTable+" WHERE recipe_id = ?", recipeID).Error; err != nil {
+		return err
+	}
+	return tx.Exec("INSERT INTO "+RecipeSearchTable+" (recipe_id, title, description, ingredients, tags) "+
+		searchDocumentSelect+" AND r.id = ?", recipeID).Error
+}
+
+// RebuildSearchIndex drops every search document and indexes all recipes
+// outside the trash again.
+func RebuildSearchIndex(tx *gorm.DB) error {
+	if !searchIndexEnabled {
+		return nil
+	}
+	if err := tx.Exec("DELETE FROM " + RecipeSearchTable).Error; err != nil {
+		return err
+	}
+	return tx.Exec("INSERT INTO " + RecipeSearchTable + " (recipe_id, title, description, ingredients, tags) " +
+		searchDocumentSelect).Error
+}
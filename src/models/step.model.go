@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type RecipeStep struct {
+	ID              string     `gorm:"type:text;primaryKey" json:"id"`
+	RecipeID        string     `gorm:"type:text;index;not null" json:"recipe_id"`
+	Position        int        `gorm:"not null;default:0" json:"position"`
+	Instruction     string     `gorm:"type:text;not null" json:"instruction"`
+	DurationMinutes *int       `json:"duration_minutes"`
+	IngredientIDs   StringList `gorm:"type:text;default:'[]'" json:"ingredient_ids"`
+	CreatedAt       time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt       time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+type StepInput struct {
+	Instruction     string   `json:"instruction" binding:"required"`
+	DurationMinutes *int     `json:"duration_minutes" binding:"omitempty,min=0"`
+	IngredientIDs   []string `json:"ingredient_ids"`
+	Position        *int     `json:"position" binding:"omitempty,min=0"`
+}
+
+type ReorderStepsInput struct {
+	StepIDs []string `json:"step_ids" binding:"required,min=1"`
+}
+
+func (s *RecipeStep) BeforeCreate(tx *gorm.DB) error {
+	if s.ID == "" {
+		s.ID = uuid.New().String()
+	}
+	if s.IngredientIDs == nil {
+		s.IngredientIDs = StringList{}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package models
+
+import (
+	"time"
+
+	"recipe-api/src/utils"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	TagTypeGeneral  = "general"
+	TagTypeCuisine  = "cuisine"
+	TagTypeCourse   = "course"
+	TagTypeDiet     = "diet"
+	TagTypeOccasion = "occasion"
+)
+
+var TagTypes = []string{TagTypeGeneral, TagTypeCuisine, TagTypeCourse, TagTypeDiet, TagTypeOccasion}
+
+type Tag struct {
+	ID        string    `gorm:"type:text;primaryKey" json:"id"`
+	Name      string    `gorm:"type:text;not null" json:"name"`
+	Slug      string    `gorm:"type:text;not null;uniqueIndex:idx_tags_type_slug" json:"slug"`
+	Type      string    `gorm:"type:text;not null;default:general;uniqueIndex:idx_tags_type_slug" json:"type"`
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+	Recipes   []Recipe  `gorm:"many2many:recipe_tags" json:"recipes,omitempty"`
+}
+
+type TagInput struct {
+	Name string `json:"name" binding:"required"`
+	Type string `json:"type"`
+}
+
+type AttachTagsInput struct {
+	TagIDs []string `json:"tag_ids" binding:"required,min=1"`
+}
+
+func IsValidTagType(tagType string) bool {
+	for _, t := range TagTypes {
+		if t == tagType {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *Tag) BeforeSave(tx *gorm.DB) error {
+	if t.ID == "" {
+		t.ID = uuid.New().String()
+	}
+	if t.Type == "" {
+		t.Type = TagTypeGeneral
+	}
+	t.Slug = utils.Slugify(t.Name)
+	return nil
+}
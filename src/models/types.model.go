@@ -0,0 +1,51 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// StringList is a []string stored as a JSON array in a TEXT column.
+type StringList []string
+
+func (l StringList) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal([]string(l))
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (l *StringList) Scan(value interface{}) error {
+	var raw []byte
+	switch v := value.(type) {
+	case nil:
+		*l = StringList{}
+		return nil
+	case string:
+		raw = []byte(v)
+	case []byte:
+		raw = v
+	default:
+		return fmt.Errorf("unsupported string list type %T", value)
+	}
+	var items []string
+	if err := json.Unmarshal(raw, &items); err != nil {
+		return err
+	}
+	*l = items
+	return nil
+}
+
+func (l StringList) Contains(item string) bool {
+	for _, v := range l {
+		if v == item {
+			return true
+		}
+	}
+	return false
+}
@@ -8,17 +8,52 @@ import (
 )
 
 type User struct {
-	ID        string    `gorm:"type:text;primaryKey" json:"id"`
-	Username  string    `gorm:"type:text;uniqueIndex;not null" json:"username" binding:"required"`
-	Email     string    `gorm:"type:text;uniqueIndex;not null" json:"email" binding:"required,email"`
-	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt time.Time `gorm:"autoUpdateTime" json:"updated_at"`
-	Recipes   []Recipe  `gorm:"foreignKey:UserID" json:"recipes,omitempty"`
+	ID             string     `gorm:"type:text;primaryKey" json:"id"`
+	Username       string     `gorm:"type:text;uniqueIndex;not null" json:"username" binding:"required"`
+	Email          string     `gorm:"type:text;uniqueIndex;not null" json:"email" binding:"required,email"`
+	Password       string     `gorm:"-" json:"password,omitempty" binding:"required,min=8"`
+	PasswordHash   string     `gorm:"type:text" json:"-"`
+	ResetTokenHash string     `gorm:"type:text;index" json:"-"`
+	ResetExpiresAt *time.Time `json:"-"`
+	Role           string     `gorm:"type:text;not null;default:user" json:"role"`
+	Banned         bool       `gorm:"default:false" json:"banned"`
+	BannedAt       *time.Time `json:"banned_at,omitempty"`
+	CreatedAt      time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+	Recipes        []Recipe   `gorm:"foreignKey:UserID" json:"recipes,omitempty"`
+}
+
+type LoginInput struct {
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+type ResetPasswordInput struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=8"`
 }
 
 func (u *User) BeforeCreate(tx *gorm.DB) error {
 	if u.ID == "" {
 		u.ID = uuid.New().String()
 	}
+	if u.Role == "" {
+		u.Role = RoleUser
+	}
 	return nil
 }
+
+func (u *User) Can(permission string) bool {
+	return HasPermission(u.Role, permission)
+}
+
+// PublicUser is the part of a user shown next to content they wrote, such as
+// ratings. It never carries email, role or ban state.
+type PublicUser struct {
+	ID       string `json:"id"`
+	Username string `json:"username"`
+}
+
+func (PublicUser) TableName() string {
+	return "users"
+}
@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"recipe-api/src/controllers"
+	"recipe-api/src/middlewares"
+	"recipe-api/src/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterAdminRoutes(rg *gin.RouterGroup) {
+	admin := rg.Group("/admin", middlewares.RequireAuth())
+	{
+		admin.GET("/stats", middlewares.RequirePermission(models.PermStatsRead), controllers.GetStats)
+		admin.DELETE("/recipes/:id", middlewares.RequirePermission(models.PermRecipesDeleteAny), controllers.DeleteRecipe)
+		admin.DELETE("/ratings/:ratingId", middlewares.RequirePermission(models.PermRatingsDeleteAny), controllers.DeleteAnyRating)
+		admin.POST("/users/:id/ban", middlewares.RequirePermission(models.PermUsersBan), controllers.BanUser)
+		admin.POST("/users/:id/unban", middlewares.RequirePermission(models.PermUsersBan), controllers.UnbanUser)
+		admin.PUT("/users/:id/role", middlewares.RequirePermission(models.PermUsersManageRoles), controllers.SetUserRole)
+		admin.POST("/users/:id/password-reset", middlewares.RequirePermission(models.PermUsersResetPassword), controllers.IssuePasswordReset)
+	}
+
+	moderation := rg.Group("/moderation", middlewares.RequireAuth(), middlewares.RequirePermission(models.PermRatingsModerate))
+	{
+		moderation.POST("/ratings/:ratingId/hide", controllers.HideRating)
+		moderation.POST("/ratings/:ratingId/unhide", controllers.UnhideRating)
+	}
+}
@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"recipe-api/src/controllers"
+	"recipe-api/src/middlewares"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterAuthRoutes(rg *gin.RouterGroup) {
+	auth := rg.Group("/auth")
+	{
+		auth.POST("/login", controllers.Login)
+		auth.POST("/reset-password", controllers.ResetPassword)
+	}
+
+	apiKeys := rg.Group("/auth/api-keys", middlewares.RequireAuth())
+	{
+		apiKeys.POST("", controllers.CreateAPIKey)
+		apiKeys.GET("", controllers.GetAPIKeys)
+		apiKeys.DELETE("/:id", controllers.RevokeAPIKey)
+	}
+}
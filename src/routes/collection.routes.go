@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"recipe-api/src/controllers"
+	"recipe-api/src/middlewares"
+	"recipe-api/src/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterCollectionRoutes(rg *gin.RouterGroup) {
+	collections := rg.Group("/collections")
+	{
+		collections.POST("", middlewares.RequireAuth(), middlewares.RequireScope(models.ScopeRecipesWrite), controllers.CreateCollection)
+		collections.GET("/:id", middlewares.OptionalAuth(), middlewares.RequireScope(models.ScopeRecipesRead), controllers.GetCollection)
+		collections.PUT("/:id", middlewares.RequireAuth(), middlewares.RequireScope(models.ScopeRecipesWrite), controllers.UpdateCollection)
+		collections.DELETE("/:id", middlewares.RequireAuth(), middlewares.RequireScope(models.ScopeRecipesWrite), controllers.DeleteCollection)
+		collections.POST("/:id/recipes", middlewares.RequireAuth(), middlewares.RequireScope(models.ScopeRecipesWrite), controllers.AddCollectionRecipe)
+		collections.PUT("/:id/recipes/reorder", middlewares.RequireAuth(), middlewares.RequireScope(models.ScopeRecipesWrite), controllers.ReorderCollection)
+		collections.PUT("/:id/recipes/:recipeId", middlewares.RequireAuth(), middlewares.RequireScope(models.ScopeRecipesWrite), controllers.UpdateCollectionRecipe)
+		collections.DELETE("/:id/recipes/:recipeId", middlewares.RequireAuth(), middlewares.RequireScope(models.ScopeRecipesWrite), controllers.RemoveCollectionRecipe)
+	}
+}
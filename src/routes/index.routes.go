@@ -18,9 +18,16 @@ func SetupRoutes(router *gin.Engine) {
 
 	api := router.Group("/api")
 
+	RegisterAuthRoutes(api)
 	RegisterRecipeRoutes(api)
+	RegisterSearchRoutes(api)
 	RegisterRatingRoutes(api)
+	RegisterStepRoutes(api)
+	RegisterRevisionRoutes(api)
+	RegisterTagRoutes(api)
+	RegisterCollectionRoutes(api)
 	RegisterUserRoutes(api)
+	RegisterAdminRoutes(api)
 
 	router.NoRoute(func(c *gin.Context) {
 		utils.ErrorResponse(c, http.StatusNotFound,
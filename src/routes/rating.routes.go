@@ -2,6 +2,8 @@ package routes
 
 import (
 	"recipe-api/src/controllers"
+	"recipe-api/src/middlewares"
+	"recipe-api/src/models"
 
 	"github.com/gin-gonic/gin"
 )
@@ -9,7 +11,9 @@ import (
 func RegisterRatingRoutes(rg *gin.RouterGroup) {
 	ratings := rg.Group("/recipes")
 	{
-		ratings.POST("/:id/ratings", controllers.AddRating)
-		ratings.GET("/:id/ratings", controllers.GetRatings)
+		ratings.POST("/:id/ratings", middlewares.RequireAuth(), middlewares.RequireScope(models.ScopeRatingsWrite), controllers.AddRating)
+		ratings.GET("/:id/ratings", middlewares.OptionalAuth(), middlewares.RequireScope(models.ScopeRatingsRead), controllers.GetRatings)
+		ratings.PUT("/:id/ratings/:ratingId", middlewares.RequireAuth(), middlewares.RequireScope(models.ScopeRatingsWrite), controllers.UpdateRating)
+		ratings.DELETE("/:id/ratings/:ratingId", middlewares.RequireAuth(), middlewares.RequireScope(models.ScopeRatingsWrite), controllers.DeleteRating)
 	}
 }
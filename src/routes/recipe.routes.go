@@ -3,6 +3,7 @@ package routes
 import (
 	"recipe-api/src/controllers"
 	"recipe-api/src/middlewares"
+	"recipe-api/src/models"
 
 	"github.com/gin-gonic/gin"
 )
@@ -10,11 +11,17 @@ import (
 func RegisterRecipeRoutes(rg *gin.RouterGroup) {
 	recipes := rg.Group("/recipes")
 	{
-		recipes.GET("/search", controllers.SearchByIngredients)
-		recipes.GET("", controllers.GetAllRecipes)
-		recipes.GET("/:id", controllers.GetRecipeByID)
-		recipes.POST("", middlewares.UploadImage(), controllers.CreateRecipe)
-		recipes.PUT("/:id", controllers.UpdateRecipe)
-		recipes.DELETE("/:id", controllers.DeleteRecipe)
+		recipes.GET("/search", middlewares.OptionalAuth(), middlewares.RequireScope(models.ScopeRecipesRead), controllers.SearchByIngredients)
+		recipes.GET("", middlewares.OptionalAuth(), middlewares.RequireScope(models.ScopeRecipesRead), controllers.GetAllRecipes)
+		recipes.GET("/trash", middlewares.RequireAuth(), middlewares.RequireScope(models.ScopeRecipesRead), controllers.GetTrash)
+		recipes.GET("/:id", middlewares.OptionalAuth(), middlewares.RequireScope(models.ScopeRecipesRead), controllers.GetRecipeByID)
+		recipes.GET("/:id/scaled", middlewares.OptionalAuth(), middlewares.RequireScope(models.ScopeRecipesRead), controllers.GetScaledRecipe)
+		recipes.GET("/:id/nutrition", middlewares.OptionalAuth(), middlewares.RequireScope(models.ScopeRecipesRead), controllers.GetRecipeNutrition)
+		recipes.GET("/:id/forks", middlewares.OptionalAuth(), middlewares.RequireScope(models.ScopeRecipesRead), controllers.GetRecipeForks)
+		recipes.POST("", middlewares.RequireAuth(), middlewares.RequireScope(models.ScopeRecipesWrite), middlewares.UploadImage(), controllers.CreateRecipe)
+		recipes.POST("/:id/fork", middlewares.RequireAuth(), middlewares.RequireScope(models.ScopeRecipesWrite), controllers.ForkRecipe)
+		recipes.PUT("/:id", middlewares.RequireAuth(), middlewares.RequireScope(models.ScopeRecipesWrite), controllers.UpdateRecipe)
+		recipes.DELETE("/:id", middlewares.RequireAuth(), middlewares.RequireScope(models.ScopeRecipesWrite), controllers.DeleteRecipe)
+		recipes.POST("/:id/restore", middlewares.RequireAuth(), middlewares.RequireScope(models.ScopeRecipesWrite), controllers.RestoreRecipe)
 	}
 }
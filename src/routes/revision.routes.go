@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"recipe-api/src/controllers"
+	"recipe-api/src/middlewares"
+	"recipe-api/src/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterRevisionRoutes(rg *gin.RouterGroup) {
+	revisions := rg.Group("/recipes/:id/revisions")
+	{
+		revisions.GET("", middlewares.OptionalAuth(), middlewares.RequireScope(models.ScopeRecipesRead), controllers.GetRevisions)
+		revisions.GET("/diff", middlewares.OptionalAuth(), middlewares.RequireScope(models.ScopeRecipesRead), controllers.DiffRevisions)
+		revisions.GET("/:rev", middlewares.OptionalAuth(), middlewares.RequireScope(models.ScopeRecipesRead), controllers.GetRevision)
+		revisions.POST("/:rev/revert", middlewares.RequireAuth(), middlewares.RequireScope(models.ScopeRecipesWrite), controllers.RevertRecipe)
+	}
+}
@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"recipe-api/src/controllers"
+	"recipe-api/src/middlewares"
+	"recipe-api/src/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterSearchRoutes(rg *gin.RouterGroup) {
+	rg.GET("/search", middlewares.OptionalAuth(), middlewares.RequireScope(models.ScopeRecipesRead), controllers.SearchRecipes)
+}
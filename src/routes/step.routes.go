@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"recipe-api/src/controllers"
+	"recipe-api/src/middlewares"
+	"recipe-api/src/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterStepRoutes(rg *gin.RouterGroup) {
+	steps := rg.Group("/recipes/:id/steps")
+	{
+		steps.GET("", middlewares.OptionalAuth(), middlewares.RequireScope(models.ScopeRecipesRead), controllers.GetSteps)
+		steps.POST("", middlewares.RequireAuth(), middlewares.RequireScope(models.ScopeRecipesWrite), controllers.AddStep)
+		steps.PUT("/reorder", middlewares.RequireAuth(), middlewares.RequireScope(models.ScopeRecipesWrite), controllers.ReorderSteps)
+		steps.PUT("/:stepId", middlewares.RequireAuth(), middlewares.RequireScope(models.ScopeRecipesWrite), controllers.UpdateStep)
+		steps.DELETE("/:stepId", middlewares.RequireAuth(), middlewares.RequireScope(models.ScopeRecipesWrite), controllers.DeleteStep)
+	}
+}
@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"recipe-api/src/controllers"
+	"recipe-api/src/middlewares"
+	"recipe-api/src/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterTagRoutes(rg *gin.RouterGroup) {
+	tags := rg.Group("/tags")
+	{
+		tags.GET("", controllers.GetTags)
+		tags.POST("", middlewares.RequireAuth(), middlewares.RequirePermission(models.PermTagsManage), controllers.CreateTag)
+		tags.PUT("/:id", middlewares.RequireAuth(), middlewares.RequirePermission(models.PermTagsManage), controllers.UpdateTag)
+		tags.DELETE("/:id", middlewares.RequireAuth(), middlewares.RequirePermission(models.PermTagsManage), controllers.DeleteTag)
+	}
+
+	recipeTags := rg.Group("/recipes/:id/tags", middlewares.RequireAuth(), middlewares.RequireScope(models.ScopeRecipesWrite))
+	{
+		recipeTags.POST("", controllers.AttachTags)
+		recipeTags.DELETE("/:tagId", controllers.DetachTag)
+	}
+}
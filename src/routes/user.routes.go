@@ -2,6 +2,8 @@ package routes
 
 import (
 	"recipe-api/src/controllers"
+	"recipe-api/src/middlewares"
+	"recipe-api/src/models"
 
 	"github.com/gin-gonic/gin"
 )
@@ -10,6 +12,8 @@ func RegisterUserRoutes(rg *gin.RouterGroup) {
 	users := rg.Group("/users")
 	{
 		users.POST("", controllers.RegisterUser)
-		users.GET("/:id", controllers.GetUserByID)
+		users.GET("/:id", middlewares.OptionalAuth(), middlewares.RequireScope(models.ScopeRecipesRead), controllers.GetUserByID)
+		users.GET("/:id/recipes", middlewares.OptionalAuth(), middlewares.RequireScope(models.ScopeRecipesRead), controllers.GetUserRecipes)
+		users.GET("/:id/collections", middlewares.OptionalAuth(), middlewares.RequireScope(models.ScopeRecipesRead), controllers.GetUserCollections)
 	}
 }
@@ -0,0 +1,137 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	jwtSecretOnce  sync.Once
+	jwtSecretBytes []byte
+)
+
+type TokenClaims struct {
+	UserID   string `json:"uid"`
+	Username string `json:"username"`
+	jwt.RegisteredClaims
+}
+
+func HashPassword(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", fmt.Errorf("failed to hash password: %w", err)
+	}
+	return string(hash), nil
+}
+
+func CheckPassword(hash, password string) bool {
+	if hash == "" {
+		return false
+	}
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
+
+func GenerateToken(userID, username string) (string, time.Time, error) {
+	expiresIn := 24 * time.Hour
+	if h := os.Getenv("JWT_EXPIRES_HOURS"); h != "" {
+		if parsed, err := strconv.Atoi(h); err == nil && parsed > 0 {
+			expiresIn = time.Duration(parsed) * time.Hour
+		}
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(expiresIn)
+	claims := TokenClaims{
+		UserID:   userID,
+		Username: username,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   userID,
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	signed, err := token.SignedString(jwtSecret())
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to sign token: %w", err)
+	}
+	return signed, expiresAt, nil
+}
+
+func ParseToken(tokenString string) (*TokenClaims, error) {
+	claims := &TokenClaims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(t *jwt.Token) (interface{}, error) {
+		return jwtSecret(), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid || claims.UserID == "" {
+		return nil, errors.New("invalid token")
+	}
+	return claims, nil
+}
+
+// jwtSecret returns JWT_SECRET, or when it is unset a random secret generated
+// once per process. Tokens signed with a generated secret stop working when
+// the server restarts.
+func jwtSecret() []byte {
+	jwtSecretOnce.Do(func() {
+		if secret := os.Getenv("JWT_SECRET"); secret != "" {
+			jwtSecretBytes = []byte(secret)
+			return
+		}
+		jwtSecretBytes = make([]byte, 32)
+		if _, err := rand.Read(jwtSecretBytes); err != nil {
+			log.Fatalf("❌ Failed to generate JWT secret: %v", err)
+		}
+		log.Println("⚠️  JWT_SECRET not set, using a random secret; tokens will not survive a restart")
+	})
+	return jwtSecretBytes
+}
+
+// GenerateAPIKey returns a new plaintext key and its display prefix. Only the
+// SHA-256 hash of the key is stored; the plaintext is shown to the user once.
+func GenerateAPIKey() (string, string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", "", fmt.Errorf("failed to generate api key: %w", err)
+	}
+	key := "rk_" + hex.EncodeToString(buf)
+	return key, key[:11], nil
+}
+
+func HashAPIKey(key string) string {
+	return hashSecret(key)
+}
+
+// GenerateResetToken returns a new one-time password reset token. As with API
+// keys, only its SHA-256 hash is stored.
+func GenerateResetToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate reset token: %w", err)
+	}
+	return "rst_" + hex.EncodeToString(buf), nil
+}
+
+func HashResetToken(token string) string {
+	return hashSecret(token)
+}
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,155 @@
+package utils
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrInvalidCursor = errors.New("invalid or expired cursor")
+
+// cursorToken is the payload behind an opaque cursor: the sort it belongs
+// to, the sort key and ID of the boundary row, and the paging direction.
+type cursorToken struct {
+	Sort     string `json:"s"`
+	Type     string `json:"t"`
+	Key      string `json:"k"`
+	ID       string `json:"id"`
+	Backward bool   `json:"b,omitempty"`
+}
+
+// KeysetSort describes one keyset-paginated ordering: a whitelisted SQL
+// expression, its direction, and how to read the key from a row. Rows with
+// equal keys are ordered by IDColumn in the same direction.
+type KeysetSort[T any] struct {
+	Name     string
+	Column   string
+	IDColumn string
+	Desc     bool
+	Key      func(*T) interface{}
+	ID       func(*T) string
+}
+
+type CursorPage[T any] struct {
+	Items      []T
+	NextCursor string
+	PrevCursor string
+}
+
+// Paginate fetches up to limit rows after (or, for a prev cursor, before) the
+// row encoded in cursor. An empty cursor starts at the first row. Keys are
+// compared as bound values, so a cursor can never change which columns are
+// queried.
+func (s KeysetSort[T]) Paginate(query *gorm.DB, cursor string, limit int) (CursorPage[T], error) {
+	var page CursorPage[T]
+	var token *cursorToken
+	if cursor != "" {
+		decoded, err := decodeCursor(cursor)
+		if err != nil || decoded.Sort != s.Name {
+			return page, ErrInvalidCursor
+		}
+		token = &decoded
+	}
+
+	backward := token != nil && token.Backward
+	ascending := !s.Desc != backward
+	direction, op := "DESC", "<"
+	if ascending {
+		direction, op = "ASC", ">"
+	}
+
+	if token != nil {
+		key, err := parseCursorKey(token.Type, token.Key)
+		if err != nil {
+			return page, ErrInvalidCursor
+		}
+		query = query.Where(
+			fmt.Sprintf("((%s) %s ? OR ((%s) = ? AND %s %s ?))", s.Column, op, s.Column, s.IDColumn, op),
+			key, key, token.ID,
+		)
+	}
+
+	var items []T
+	err := query.Order(s.Column + " " + direction).
+		Order(s.IDColumn + " " + direction).
+		Limit(limit + 1).
+		Find(&items).Error
+	if err != nil {
+		return page, err
+	}
+
+	hasMore := len(items) > limit
+	if hasMore {
+		items = items[:limit]
+	}
+	if backward {
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+	}
+	page.Items = items
+	if len(items) == 0 {
+		return page, nil
+	}
+
+	first, last := &items[0], &items[len(items)-1]
+	if backward {
+		page.NextCursor = s.encode(last, false)
+		if hasMore {
+			page.PrevCursor = s.encode(first, true)
+		}
+	} else {
+		if hasMore {
+			page.NextCursor = s.encode(last, false)
+		}
+		if token != nil {
+			page.PrevCursor = s.encode(first, true)
+		}
+	}
+	return page, nil
+}
+
+func (s KeysetSort[T]) encode(item *T, backward bool) string {
+	token := cursorToken{Sort: s.Name, ID: s.ID(item), Backward: backward}
+	switch key := s.Key(item).(type) {
+	case time.Time:
+		token.Type, token.Key = "t", key.Format(time.RFC3339Nano)
+	case float64:
+		token.Type, token.Key = "f", strconv.FormatFloat(key, 'g', -1, 64)
+	case int:
+		token.Type, token.Key = "i", strconv.Itoa(key)
+	default:
+		token.Type, token.Key = "s", fmt.Sprint(key)
+	}
+	raw, _ := json.Marshal(token)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func decodeCursor(cursor string) (cursorToken, error) {
+	var token cursorToken
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return token, err
+	}
+	err = json.Unmarshal(raw, &token)
+	return token, err
+}
+
+func parseCursorKey(keyType, key string) (interface{}, error) {
+	switch keyType {
+	case "t":
+		return time.Parse(time.RFC3339Nano, key)
+	case "f":
+		return strconv.ParseFloat(key, 64)
+	case "i":
+		return strconv.Atoi(key)
+	case "s":
+		return key, nil
+	}
+	return nil, ErrInvalidCursor
+}
@@ -0,0 +1,168 @@
+package utils
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type cursorRow struct {
+	ID    string `gorm:"primaryKey"`
+	Score float64
+}
+
+var cursorRowsByScore = KeysetSort[cursorRow]{
+	Name:     "score",
+	Column:   "score",
+	IDColumn: "id",
+	Desc:     true,
+	Key:      func(r *cursorRow) interface{} { return r.Score },
+	ID:       func(r *cursorRow) string { return r.ID },
+}
+
+func newCursorDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "cursor.db")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	if err := db.AutoMigrate(&cursorRow{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	rows := []cursorRow{{"a", 3}, {"b", 2}, {"c", 2}, {"d", 1}, {"e", 0}}
+	if err := db.Create(&rows).Error; err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+	return db
+}
+
+func encodeCursorToken(t *testing.T, token cursorToken) string {
+	t.Helper()
+	raw, err := json.Marshal(token)
+	if err != nil {
+		t.Fatalf("marshal cursor: %v", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+func TestCursorKeyRoundTrip(t *testing.T) {
+	created := time.Date(2024, 3, 1, 12, 30, 0, 123456789, time.UTC)
+	tests := []struct {
+		key  interface{}
+		want interface{}
+	}{
+		{created, created},
+		{4.25, 4.25},
+		{0.1 + 0.2, 0.1 + 0.2},
+		{42, 42},
+		{"Chocolate Cake", "Chocolate Cake"},
+	}
+	for _, tt := range tests {
+		sort := KeysetSort[cursorRow]{
+			Name: "test",
+			Key:  func(*cursorRow) interface{} { return tt.key },
+			ID:   func(r *cursorRow) string { return r.ID },
+		}
+		cursor := sort.encode(&cursorRow{ID: "row-1"}, true)
+
+		token, err := decodeCursor(cursor)
+		if err != nil {
+			t.Fatalf("decode %v: %v", tt.key, err)
+		}
+		if token.Sort != "test" || token.ID != "row-1" || !token.Backward {
+			t.Errorf("token for %v = %+v", tt.key, token)
+		}
+		key, err := parseCursorKey(token.Type, token.Key)
+		if err != nil {
+			t.Fatalf("parse key %v: %v", tt.key, err)
+		}
+		if got, ok := key.(time.Time); ok {
+			if !got.Equal(tt.want.(time.Time)) {
+				t.Errorf("key = %v, want %v", got, tt.want)
+			}
+		} else if !reflect.DeepEqual(key, tt.want) {
+			t.Errorf("key = %#v, want %#v", key, tt.want)
+		}
+	}
+}
+
+func TestKeysetPaginate(t *testing.T) {
+	db := newCursorDB(t)
+
+	ids := func(page CursorPage[cursorRow]) []string {
+		var out []string
+		for _, row := range page.Items {
+			out = append(out, row.ID)
+		}
+		return out
+	}
+	fetch := func(cursor string) CursorPage[cursorRow] {
+		t.Helper()
+		page, err := cursorRowsByScore.Paginate(db.Model(&cursorRow{}), cursor, 2)
+		if err != nil {
+			t.Fatalf("paginate: %v", err)
+		}
+		return page
+	}
+
+	// Rows with equal scores are ordered by ID in the same direction.
+	first := fetch("")
+	if got := ids(first); !reflect.DeepEqual(got, []string{"a", "c"}) || first.PrevCursor != "" || first.NextCursor == "" {
+		t.Fatalf("first page = %v (prev %q, next %q)", got, first.PrevCursor, first.NextCursor)
+	}
+	second := fetch(first.NextCursor)
+	if got := ids(second); !reflect.DeepEqual(got, []string{"b", "d"}) || second.PrevCursor == "" || second.NextCursor == "" {
+		t.Fatalf("second page = %v (prev %q, next %q)", got, second.PrevCursor, second.NextCursor)
+	}
+	last := fetch(second.NextCursor)
+	if got := ids(last); !reflect.DeepEqual(got, []string{"e"}) || last.PrevCursor == "" || last.NextCursor != "" {
+		t.Fatalf("last page = %v (prev %q, next %q)", got, last.PrevCursor, last.NextCursor)
+	}
+
+	back := fetch(last.PrevCursor)
+	if got := ids(back); !reflect.DeepEqual(got, []string{"b", "d"}) || back.PrevCursor == "" || back.NextCursor == "" {
+		t.Fatalf("back to second page = %v (prev %q, next %q)", got, back.PrevCursor, back.NextCursor)
+	}
+	start := fetch(back.PrevCursor)
+	if got := ids(start); !reflect.DeepEqual(got, []string{"a", "c"}) || start.PrevCursor != "" || start.NextCursor == "" {
+		t.Fatalf("back to first page = %v (prev %q, next %q)", got, start.PrevCursor, start.NextCursor)
+	}
+}
+
+func TestKeysetPaginateRejectsInvalidCursors(t *testing.T) {
+	db := newCursorDB(t)
+	valid := cursorRowsByScore.encode(&cursorRow{ID: "b", Score: 2}, false)
+	tampered := []byte(valid)
+	tampered[len(tampered)/2] ^= 1
+
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{"not base64", "not a cursor!"},
+		{"not json", base64.RawURLEncoding.EncodeToString([]byte("score:2"))},
+		{"flipped byte", string(tampered)},
+		{"other sort", encodeCursorToken(t, cursorToken{Sort: "newest", Type: "f", Key: "2", ID: "b"})},
+		{"unknown key type", encodeCursorToken(t, cursorToken{Sort: "score", Type: "x", Key: "2", ID: "b"})},
+		{"float key not a number", encodeCursorToken(t, cursorToken{Sort: "score", Type: "f", Key: "high", ID: "b"})},
+		{"int key not an integer", encodeCursorToken(t, cursorToken{Sort: "score", Type: "i", Key: "1.5", ID: "b"})},
+		{"time key not a time", encodeCursorToken(t, cursorToken{Sort: "score", Type: "t", Key: "yesterday", ID: "b"})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := cursorRowsByScore.Paginate(db.Model(&cursorRow{}), tt.cursor, 2); !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("err = %v, want ErrInvalidCursor", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,201 @@
+package utils
+
+import (
+	"sort"
+	"strings"
+)
+
+const (
+	LabelVegetarian     = "vegetarian"
+	LabelVegan          = "vegan"
+	AllergenLabelPrefix = "contains:"
+)
+
+type dietaryRule struct {
+	keywords []string
+	// exceptions are phrases that overlap a keyword but do not trigger the
+	// rule, e.g. "peanut butter" or "coconut milk" for dairy. Only the
+	// keyword they cover is ignored.
+	exceptions []string
+	// clearedBy are phrases that rule the whole ingredient out, e.g.
+	// "gluten free" in "gluten free flour".
+	clearedBy []string
+}
+
+// allergenRules maps each allergen to the ingredient words that indicate it.
+// Matching is on whole words of the canonical ingredient name.
+var allergenRules = map[string]dietaryRule{
+	"gluten": {
+		keywords: []string{"wheat", "flour", "bread", "breadcrumbs", "panko", "pasta", "spaghetti",
+			"macaroni", "penne", "fettuccine", "linguine", "lasagna", "noodles", "couscous", "semolina",
+			"barley", "rye", "bulgur", "farro", "spelt", "seitan", "soy sauce", "beer", "tortilla",
+			"tortillas", "croutons", "crackers", "pita", "baguette", "brioche", "pastry"},
+		exceptions: []string{"rice flour", "almond flour", "coconut flour", "corn flour", "chickpea flour",
+			"buckwheat flour", "oat flour", "tapioca flour", "potato flour", "rice noodles",
+			"corn tortilla", "corn tortillas", "rice pasta"},
+		clearedBy: []string{"gluten free", "tamari"},
+	},
+	"nuts": {
+		keywords: []string{"nut", "nuts", "almond", "almonds", "walnut", "walnuts", "pecan", "pecans",
+			"cashew", "cashews", "pistachio", "pistachios", "hazelnut", "hazelnuts", "macadamia",
+			"brazil nut", "pine nut", "pine nuts", "praline", "marzipan", "nutella"},
+		exceptions: []string{"nutmeg", "butternut", "butternut squash", "coconut", "water chestnut"},
+	},
+	"peanuts": {
+		keywords: []string{"peanut", "peanuts", "peanut butter", "groundnut", "groundnuts"},
+	},
+	"dairy": {
+		keywords: []string{"milk", "butter", "cream", "cheese", "yogurt", "yoghurt", "ghee", "buttermilk",
+			"parmesan", "mozzarella", "cheddar", "ricotta", "feta", "mascarpone", "brie", "gouda",
+			"whey", "casein", "creme fraiche", "custard", "ice cream"},
+		exceptions: []string{"coconut milk", "almond milk", "soy milk", "oat milk", "rice milk",
+			"cashew milk", "peanut butter", "almond butter", "cashew butter", "cocoa butter",
+			"apple butter", "shea butter", "coconut cream", "cream of tartar", "vegan butter",
+			"vegan cheese", "nutritional yeast", "butternut", "butternut squash",
+			"butter beans", "butter lettuce"},
+		clearedBy: []string{"dairy free"},
+	},
+	"eggs": {
+		keywords: []string{"egg", "eggs", "egg yolk", "egg yolks", "egg white", "egg whites",
+			"mayonnaise", "mayo", "meringue", "aioli"},
+		exceptions: []string{"eggplant", "vegan mayo", "vegan mayonnaise"},
+		clearedBy:  []string{"egg free"},
+	},
+	"soy": {
+		keywords: []string{"soy", "soya", "soybean", "soybeans", "tofu", "tempeh", "edamame", "miso",
+			"soy sauce", "tamari"},
+	},
+	"fish": {
+		keywords: []string{"fish", "salmon", "tuna", "cod", "anchovy", "anchovies", "sardine",
+			"sardines", "trout", "halibut", "mackerel", "tilapia", "haddock", "snapper", "bass",
+			"fish sauce", "worcestershire"},
+	},
+	"shellfish": {
+		keywords: []string{"shrimp", "shrimps", "prawn", "prawns", "crab", "lobster", "clam", "clams",
+			"mussel", "mussels", "oyster", "oysters", "scallop", "scallops", "squid", "calamari",
+			"octopus", "crawfish", "crayfish"},
+	},
+	"sesame": {
+		keywords: []string{"sesame", "tahini", "sesame oil", "sesame seeds"},
+	},
+}
+
+// meatRule rules out the vegetarian label, together with fish and shellfish.
+var meatRule = dietaryRule{
+	keywords: []string{"meat", "beef", "steak", "pork", "bacon", "ham", "lamb", "mutton", "veal",
+		"chicken", "turkey", "duck", "goose", "venison", "sausage", "sausages", "chorizo", "salami",
+		"pepperoni", "prosciutto", "pancetta", "gelatin", "gelatine", "lard", "suet", "bone broth",
+		"mince", "ground beef", "ground pork", "ground turkey", "ribs", "brisket", "meatballs"},
+	exceptions: []string{"vegan sausage", "vegetarian sausage", "plant based meat", "beyond meat",
+		"mushroom", "coconut meat"},
+}
+
+// animalProductRule covers non-meat animal products that rule out vegan.
+var animalProductRule = dietaryRule{
+	keywords: []string{"honey", "beeswax"},
+}
+
+// matches reports whether the padded canonical name (" name ") triggers the
+// rule: some keyword occurrence is not covered by an exception and no
+// clearing phrase is present.
+func (r dietaryRule) matches(padded string) bool {
+	for _, phrase := range r.clearedBy {
+		if strings.Contains(padded, " "+phrase+" ") {
+			return false
+		}
+	}
+
+	var covered [][2]int
+	for _, exception := range r.exceptions {
+		for _, start := range phraseOffsets(padded, exception) {
+			covered = append(covered, [2]int{start, start + len(exception)})
+		}
+	}
+
+	for _, keyword := range r.keywords {
+		for _, start := range phraseOffsets(padded, keyword) {
+			end := start + len(keyword)
+			excepted := false
+			for _, span := range covered {
+				if start < span[1] && span[0] < end {
+					excepted = true
+					break
+				}
+			}
+			if !excepted {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// phraseOffsets returns where phrase occurs as whole words in padded.
+func phraseOffsets(padded, phrase string) []int {
+	var offsets []int
+	needle := " " + phrase + " "
+	for from := 0; ; {
+		i := strings.Index(padded[from:], needle)
+		if i < 0 {
+			return offsets
+		}
+		offsets = append(offsets, from+i+1)
+		from += i + 1
+	}
+}
+
+// DietaryLabels derives allergen and diet labels from a recipe's ingredient
+// names using the built-in rules: "contains:<allergen>" for every allergen
+// found, plus "vegetarian" and "vegan" when no ingredient rules them out.
+// Labels are returned sorted.
+func DietaryLabels(ingredientNames []string) []string {
+	allergens := map[string]bool{}
+	hasMeat, hasAnimalProduct := false, false
+
+	for _, name := range ingredientNames {
+		padded := " " + CanonicalIngredientName(name) + " "
+		for allergen, rule := range allergenRules {
+			if rule.matches(padded) {
+				allergens[allergen] = true
+			}
+		}
+		if meatRule.matches(padded) {
+			hasMeat = true
+		}
+		if animalProductRule.matches(padded) {
+			hasAnimalProduct = true
+		}
+	}
+
+	labels := []string{}
+	for allergen := range allergens {
+		labels = append(labels, AllergenLabelPrefix+allergen)
+	}
+
+	vegetarian := !hasMeat && !allergens["fish"] && !allergens["shellfish"]
+	if vegetarian {
+		labels = append(labels, LabelVegetarian)
+		if !allergens["dairy"] && !allergens["eggs"] && !hasAnimalProduct {
+			labels = append(labels, LabelVegan)
+		}
+	}
+
+	sort.Strings(labels)
+	return labels
+}
+
+// IsKnownAllergen reports whether the rules table has an allergen by name.
+func IsKnownAllergen(allergen string) bool {
+	_, ok := allergenRules[allergen]
+	return ok
+}
+
+// KnownAllergens lists the allergens of the rules table, sorted.
+func KnownAllergens() []string {
+	allergens := make([]string, 0, len(allergenRules))
+	for allergen := range allergenRules {
+		allergens = append(allergens, allergen)
+	}
+	sort.Strings(allergens)
+	return allergens
+}
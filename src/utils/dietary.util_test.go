@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDietaryLabels(t *testing.T) {
+	tests := []struct {
+		ingredient string
+		label      string
+		want       bool
+	}{
+		{"all-purpose flour", "contains:gluten", true},
+		{"gluten-free flour", "contains:gluten", false},
+		{"gluten free soy sauce", "contains:gluten", false},
+		{"tamari soy sauce", "contains:gluten", false},
+		{"soy sauce", "contains:gluten", true},
+		{"rice flour", "contains:gluten", false},
+		{"butter", "contains:dairy", true},
+		{"dairy-free butter", "contains:dairy", false},
+		{"peanut butter", "contains:dairy", false},
+		{"coconut milk", "contains:dairy", false},
+		{"egg-free mayonnaise", "contains:eggs", false},
+		{"eggplant", "contains:eggs", false},
+		{"eggs", "contains:eggs", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ingredient, func(t *testing.T) {
+			labels := DietaryLabels([]string{tt.ingredient})
+			got := false
+			for _, label := range labels {
+				if label == tt.label {
+					got = true
+				}
+			}
+			if got != tt.want {
+				t.Errorf("DietaryLabels(%q) = [%s], want %s present: %v",
+					tt.ingredient, strings.Join(labels, ", "), tt.label, tt.want)
+			}
+		})
+	}
+}
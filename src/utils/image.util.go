@@ -2,9 +2,11 @@ package utils
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 
 	"github.com/disintegration/imaging"
 	"github.com/google/uuid"
@@ -18,11 +20,8 @@ func ProcessImage(inputPath string) (string, error) {
 
 	maxWidth := 800
 	quality := 80
-	uploadDir := os.Getenv("UPLOAD_DIR")
+	uploadDir := uploadDirectory()
 
-	if uploadDir == "" {
-		uploadDir = "./public/temp"
-	}
 	if w := os.Getenv("IMG_MAX_WIDTH"); w != "" {
 		if parsed, err := strconv.Atoi(w); err == nil {
 			maxWidth = parsed
@@ -52,3 +51,71 @@ func ProcessImage(inputPath string) (string, error) {
 
 	return outputFilename, nil
 }
+
+const uploadURLPrefix = "/uploads/"
+
+func uploadDirectory() string {
+	if dir := os.Getenv("UPLOAD_DIR"); dir != "" {
+		return dir
+	}
+	return "./public/temp"
+}
+
+// CopyImage duplicates an uploaded image under a new name and returns its
+// URL, so a copied recipe does not share a file with the original. URLs that
+// do not point into the upload directory are returned unchanged.
+func CopyImage(imageURL string) (string, error) {
+	name, ok := uploadedFilename(imageURL)
+	if !ok {
+		return imageURL, nil
+	}
+
+	src, err := os.Open(filepath.Join(uploadDirectory(), name))
+	if err != nil {
+		return "", fmt.Errorf("failed to open image: %w", err)
+	}
+	defer src.Close()
+
+	outputFilename := fmt.Sprintf("recipe_%s%s", uuid.New().String()[:8], filepath.Ext(name))
+	dst, err := os.Create(filepath.Join(uploadDirectory(), outputFilename))
+	if err != nil {
+		return "", fmt.Errorf("failed to create image copy: %w", err)
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		_ = os.Remove(dst.Name())
+		return "", fmt.Errorf("failed to copy image: %w", err)
+	}
+	if err := dst.Close(); err != nil {
+		return "", fmt.Errorf("failed to copy image: %w", err)
+	}
+
+	return uploadURLPrefix + outputFilename, nil
+}
+
+// RemoveImage deletes the file behind an uploaded image URL. Missing files
+// and URLs outside the upload directory are ignored.
+func RemoveImage(imageURL string) error {
+	name, ok := uploadedFilename(imageURL)
+	if !ok {
+		return nil
+	}
+	err := os.Remove(filepath.Join(uploadDirectory(), name))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// uploadedFilename extracts the file name from an /uploads/ URL, refusing
+// anything that could escape the upload directory.
+func uploadedFilename(imageURL string) (string, bool) {
+	if !strings.HasPrefix(imageURL, uploadURLPrefix) {
+		return "", false
+	}
+	name := strings.TrimPrefix(imageURL, uploadURLPrefix)
+	if name == "" || name != filepath.Base(name) || name == "." || name == ".." {
+		return "", false
+	}
+	return name, true
+}
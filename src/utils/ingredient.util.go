@@ -0,0 +1,284 @@
+package utils
+
+import (
+	"errors"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// CanonicalIngredientName normalizes an ingredient name for matching:
+// lower-cased, punctuation stripped and whitespace collapsed, so
+// "  Green-Onion " and "green onion" compare equal.
+func CanonicalIngredientName(name string) string {
+	cleaned := strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsLetter(r), unicode.IsDigit(r):
+			return unicode.ToLower(r)
+		default:
+			return ' '
+		}
+	}, name)
+	return strings.Join(strings.Fields(cleaned), " ")
+}
+
+type ParsedIngredient struct {
+	Quantity    *float64
+	QuantityMax *float64
+	Unit        string
+	Name        string
+	Note        string
+}
+
+var unicodeFractions = map[rune]string{
+	'¼': "1/4", '½': "1/2", '¾': "3/4",
+	'⅐': "1/7", '⅑': "1/9", '⅒': "1/10",
+	'⅓': "1/3", '⅔': "2/3",
+	'⅕': "1/5", '⅖': "2/5", '⅗': "3/5", '⅘': "4/5",
+	'⅙': "1/6", '⅚': "5/6",
+	'⅛': "1/8", '⅜': "3/8", '⅝': "5/8", '⅞': "7/8",
+}
+
+// unitAliases maps every accepted spelling (lower-cased, without a trailing
+// period) to the canonical unit name stored on ingredients.
+var unitAliases = map[string]string{
+	"tsp": "tsp", "tsps": "tsp", "teaspoon": "tsp", "teaspoons": "tsp",
+	"tbsp": "tbsp", "tbsps": "tbsp", "tbs": "tbsp", "tbl": "tbsp", "tablespoon": "tbsp", "tablespoons": "tbsp",
+	"cup": "cup", "cups": "cup", "c": "cup",
+	"fl oz": "fl oz", "fluid ounce": "fl oz", "fluid ounces": "fl oz", "floz": "fl oz",
+	"pint": "pt", "pints": "pt", "pt": "pt", "pts": "pt",
+	"quart": "qt", "quarts": "qt", "qt": "qt", "qts": "qt",
+	"gallon": "gal", "gallons": "gal", "gal": "gal", "gals": "gal",
+	"ml": "ml", "milliliter": "ml", "milliliters": "ml", "millilitre": "ml", "millilitres": "ml",
+	"l": "l", "liter": "l", "liters": "l", "litre": "l", "litres": "l",
+	"g": "g", "gr": "g", "gram": "g", "grams": "g", "gramme": "g", "grammes": "g",
+	"kg": "kg", "kgs": "kg", "kilogram": "kg", "kilograms": "kg",
+	"mg": "mg", "milligram": "mg", "milligrams": "mg",
+	"oz": "oz", "ounce": "oz", "ounces": "oz",
+	"lb": "lb", "lbs": "lb", "pound": "lb", "pounds": "lb",
+	"pinch": "pinch", "pinches": "pinch",
+	"dash": "dash", "dashes": "dash",
+	"clove": "clove", "cloves": "clove",
+	"can": "can", "cans": "can",
+	"slice": "slice", "slices": "slice",
+	"stick": "stick", "sticks": "stick",
+	"bunch": "bunch", "bunches": "bunch",
+	"sprig": "sprig", "sprigs": "sprig",
+	"handful": "handful", "handfuls": "handful",
+	"package": "package", "packages": "package", "pkg": "package",
+}
+
+var sizeWords = map[string]bool{
+	"small": true, "medium": true, "large": true,
+	"extra-large": true, "xl": true, "jumbo": true,
+}
+
+var numberRangePattern = regexp.MustCompile(`(\d)\s*[-–—]\s*(\d)`)
+
+// ParseIngredientLine splits a free-text line such as
+// "2 1/2 cups all-purpose flour, sifted" into quantity, unit, name and note.
+// It understands mixed numbers, unicode fractions ("1½"), ranges ("2-3",
+// "2 to 3"), unit aliases and size words ("3 large eggs"). Anything it
+// cannot interpret is left in the name, so parsing never fails.
+func ParseIngredientLine(line string) ParsedIngredient {
+	var parsed ParsedIngredient
+	var notes []string
+
+	text := strings.TrimSpace(line)
+
+	var b strings.Builder
+	for _, r := range text {
+		if frac, ok := unicodeFractions[r]; ok {
+			b.WriteString(" " + frac + " ")
+			continue
+		}
+		if r == '⁄' {
+			r = '/'
+		}
+		b.WriteRune(r)
+	}
+	text = b.String()
+
+	for {
+		open := strings.Index(text, "(")
+		if open < 0 {
+			break
+		}
+		end := strings.Index(text[open:], ")")
+		if end < 0 {
+			break
+		}
+		if inner := strings.TrimSpace(text[open+1 : open+end]); inner != "" {
+			notes = append(notes, inner)
+		}
+		text = text[:open] + " " + text[open+end+1:]
+	}
+
+	if head, tail, found := strings.Cut(text, ","); found {
+		text = head
+		if tail = strings.TrimSpace(tail); tail != "" {
+			notes = append(notes, tail)
+		}
+	}
+
+	text = numberRangePattern.ReplaceAllString(text, "$1 - $2")
+	tokens := strings.Fields(text)
+	i := 0
+
+	if value, next, ok := parseQuantity(tokens, i); ok {
+		parsed.Quantity = &value
+		i = next
+		if i < len(tokens) && (tokens[i] == "-" || strings.EqualFold(tokens[i], "to")) {
+			if upper, next, ok := parseQuantity(tokens, i+1); ok {
+				parsed.QuantityMax = &upper
+				i = next
+			}
+		}
+	}
+
+	if parsed.Quantity != nil {
+		if unit, next, ok := parseUnit(tokens, i); ok {
+			parsed.Unit = unit
+			i = next
+			if i < len(tokens) && strings.EqualFold(tokens[i], "of") {
+				i++
+			}
+		}
+	}
+
+	var sizes []string
+	for i < len(tokens) && sizeWords[strings.ToLower(tokens[i])] {
+		sizes = append(sizes, strings.ToLower(tokens[i]))
+		i++
+	}
+	if len(sizes) > 0 {
+		notes = append([]string{strings.Join(sizes, " ")}, notes...)
+	}
+
+	rest := tokens[i:]
+	if n := len(rest); n > 2 && strings.EqualFold(rest[n-2], "to") && strings.EqualFold(rest[n-1], "taste") {
+		rest = rest[:n-2]
+		notes = append(notes, "to taste")
+	}
+
+	parsed.Name = strings.Join(rest, " ")
+	if parsed.Name == "" {
+		parsed = ParsedIngredient{Name: strings.TrimSpace(line)}
+		return parsed
+	}
+	parsed.Note = strings.Join(notes, ", ")
+	return parsed
+}
+
+// ValidateQuantity checks an ingredient's amount: an optional quantity must
+// be a finite number above zero, and an upper bound needs a quantity and may
+// not be below it. Parsed lines and structured input both go through it.
+func ValidateQuantity(quantity, quantityMax *float64) error {
+	if quantity == nil {
+		if quantityMax != nil {
+			return errors.New("quantity_max needs a quantity")
+		}
+		return nil
+	}
+	if !isPositiveFinite(*quantity) {
+		return errors.New("quantity must be a number above zero")
+	}
+	if quantityMax != nil {
+		if !isPositiveFinite(*quantityMax) {
+			return errors.New("quantity_max must be a number above zero")
+		}
+		if *quantityMax < *quantity {
+			return errors.New("quantity_max must not be less than quantity")
+		}
+	}
+	return nil
+}
+
+func isPositiveFinite(v float64) bool {
+	return v > 0 && !math.IsInf(v, 0) && !math.IsNaN(v)
+}
+
+// parseQuantity reads a whole number, decimal, fraction or mixed number
+// ("2 1/2") starting at tokens[i].
+func parseQuantity(tokens []string, i int) (float64, int, bool) {
+	if i >= len(tokens) {
+		return 0, i, false
+	}
+	value, ok := parseNumber(tokens[i])
+	if !ok {
+		return 0, i, false
+	}
+	i++
+	if !strings.Contains(tokens[i-1], "/") && i < len(tokens) && strings.Contains(tokens[i], "/") {
+		if frac, ok := parseNumber(tokens[i]); ok {
+			value += frac
+			i++
+		}
+	}
+	return value, i, true
+}
+
+// parseNumber reads a non-negative decimal ("2", "0.5") or fraction ("1/2").
+// Signs, exponents, hex and ParseFloat's "nan"/"inf" spellings are rejected
+// so every quantity is a finite, non-negative number.
+func parseNumber(token string) (float64, bool) {
+	if num, den, found := strings.Cut(token, "/"); found {
+		n, ok1 := parseDecimal(num)
+		d, ok2 := parseDecimal(den)
+		if !ok1 || !ok2 || d == 0 {
+			return 0, false
+		}
+		return n / d, true
+	}
+	return parseDecimal(token)
+}
+
+func parseDecimal(s string) (float64, bool) {
+	digits, dots := 0, 0
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			digits++
+		case r == '.':
+			dots++
+		default:
+			return 0, false
+		}
+	}
+	if digits == 0 || dots > 1 {
+		return 0, false
+	}
+	value, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsInf(value, 0) {
+		return 0, false
+	}
+	return value, true
+}
+
+// parseUnit matches one- or two-word unit aliases at tokens[i]. Single-letter
+// "T" and "t" follow the cookbook convention of tablespoon and teaspoon.
+func parseUnit(tokens []string, i int) (string, int, bool) {
+	if i >= len(tokens) {
+		return "", i, false
+	}
+	if i+1 < len(tokens) {
+		pair := strings.ToLower(strings.TrimSuffix(tokens[i], ".") + " " + strings.TrimSuffix(tokens[i+1], "."))
+		if unit, ok := unitAliases[pair]; ok {
+			return unit, i + 2, true
+		}
+	}
+
+	word := strings.TrimSuffix(tokens[i], ".")
+	switch word {
+	case "T":
+		return "tbsp", i + 1, true
+	case "t":
+		return "tsp", i + 1, true
+	}
+	if unit, ok := unitAliases[strings.ToLower(word)]; ok {
+		return unit, i + 1, true
+	}
+	return "", i, false
+}
@@ -0,0 +1,107 @@
+package utils
+
+import (
+	"math"
+	"strconv"
+	"testing"
+)
+
+func TestParseIngredientLine(t *testing.T) {
+	num := func(v float64) *float64 { return &v }
+
+	tests := []struct {
+		line string
+		want ParsedIngredient
+	}{
+		{"2 1/2 cups all-purpose flour, sifted", ParsedIngredient{Quantity: num(2.5), Unit: "cup", Name: "all-purpose flour", Note: "sifted"}},
+		{"3 large eggs", ParsedIngredient{Quantity: num(3), Name: "eggs", Note: "large"}},
+		{"1½ tsp salt", ParsedIngredient{Quantity: num(1.5), Unit: "tsp", Name: "salt"}},
+		{"¾ cup sugar", ParsedIngredient{Quantity: num(0.75), Unit: "cup", Name: "sugar"}},
+		{"1⁄3 cup milk", ParsedIngredient{Quantity: num(1.0 / 3), Unit: "cup", Name: "milk"}},
+		{"0.5 kg potatoes", ParsedIngredient{Quantity: num(0.5), Unit: "kg", Name: "potatoes"}},
+		{"2-3 cloves garlic, minced", ParsedIngredient{Quantity: num(2), QuantityMax: num(3), Unit: "clove", Name: "garlic", Note: "minced"}},
+		{"2 to 3 tbsp olive oil", ParsedIngredient{Quantity: num(2), QuantityMax: num(3), Unit: "tbsp", Name: "olive oil"}},
+		{"1 (14 oz) can diced tomatoes", ParsedIngredient{Quantity: num(1), Unit: "can", Name: "diced tomatoes", Note: "14 oz"}},
+		{"3 T butter", ParsedIngredient{Quantity: num(3), Unit: "tbsp", Name: "butter"}},
+		{"1 t vanilla", ParsedIngredient{Quantity: num(1), Unit: "tsp", Name: "vanilla"}},
+		{"2 fl. oz. rum", ParsedIngredient{Quantity: num(2), Unit: "fl oz", Name: "rum"}},
+		{"1 cup of rice", ParsedIngredient{Quantity: num(1), Unit: "cup", Name: "rice"}},
+		{"salt to taste", ParsedIngredient{Name: "salt", Note: "to taste"}},
+		{"fresh basil", ParsedIngredient{Name: "fresh basil"}},
+		{"2 cups", ParsedIngredient{Name: "2 cups"}},
+
+		// Malformed numbers are not quantities and stay in the name.
+		{"nan cups flour", ParsedIngredient{Name: "nan cups flour"}},
+		{"inf eggs", ParsedIngredient{Name: "inf eggs"}},
+		{"infinity eggs", ParsedIngredient{Name: "infinity eggs"}},
+		{"-1 cup sugar", ParsedIngredient{Name: "-1 cup sugar"}},
+		{"-1/2 cup sugar", ParsedIngredient{Name: "-1/2 cup sugar"}},
+		{"1/-2 cup sugar", ParsedIngredient{Name: "1/-2 cup sugar"}},
+		{"1/0 cup milk", ParsedIngredient{Name: "1/0 cup milk"}},
+		{"1e3 g flour", ParsedIngredient{Name: "1e3 g flour"}},
+		{"0x10 eggs", ParsedIngredient{Name: "0x10 eggs"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			got := ParseIngredientLine(tt.line)
+			if !sameQuantity(got.Quantity, tt.want.Quantity) || !sameQuantity(got.QuantityMax, tt.want.QuantityMax) ||
+				got.Unit != tt.want.Unit || got.Name != tt.want.Name || got.Note != tt.want.Note {
+				t.Errorf("ParseIngredientLine(%q) = %s, want %s", tt.line, describe(got), describe(tt.want))
+			}
+		})
+	}
+}
+
+func sameQuantity(a, b *float64) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	diff := *a - *b
+	return diff < 1e-9 && diff > -1e-9
+}
+
+func describe(p ParsedIngredient) string {
+	return "{quantity: " + describeQuantity(p.Quantity) + ", max: " + describeQuantity(p.QuantityMax) +
+		", unit: " + p.Unit + ", name: " + p.Name + ", note: " + p.Note + "}"
+}
+
+func TestValidateQuantity(t *testing.T) {
+	num := func(v float64) *float64 { return &v }
+
+	tests := []struct {
+		name        string
+		quantity    *float64
+		quantityMax *float64
+		valid       bool
+	}{
+		{"no amount", nil, nil, true},
+		{"quantity only", num(2), nil, true},
+		{"range", num(2), num(3), true},
+		{"equal bounds", num(2), num(2), true},
+		{"zero", num(0), nil, false},
+		{"negative", num(-3), nil, false},
+		{"infinite", num(math.Inf(1)), nil, false},
+		{"not a number", num(math.NaN()), nil, false},
+		{"max below quantity", num(3), num(2), false},
+		{"negative max", num(1), num(-2), false},
+		{"max without quantity", nil, num(2), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateQuantity(tt.quantity, tt.quantityMax)
+			if (err == nil) != tt.valid {
+				t.Errorf("ValidateQuantity(%s, %s) = %v, want valid %v",
+					describeQuantity(tt.quantity), describeQuantity(tt.quantityMax), err, tt.valid)
+			}
+		})
+	}
+}
+
+func describeQuantity(v *float64) string {
+	if v == nil {
+		return "nil"
+	}
+	return strconv.FormatFloat(*v, 'g', -1, 64)
+}
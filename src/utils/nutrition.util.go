@@ -0,0 +1,240 @@
+package utils
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// nutrientCSV is a compact USDA-style table with values per 100 g, plus the
+// typical weight of one item and a density for volume measures.
+//
+//go:embed data/nutrients.csv
+var nutrientCSV []byte
+
+type nutrientRow struct {
+	name      string
+	per100g   NutrientTotals
+	gramsEach float64
+	density   float64
+}
+
+var (
+	nutrientTable map[string]nutrientRow
+	nutrientOnce  sync.Once
+)
+
+// unitGrams gives a default weight for count-like units when the dataset has
+// no per-item weight for the ingredient.
+var unitGrams = map[string]float64{
+	"clove":   5,
+	"slice":   30,
+	"stick":   113,
+	"can":     400,
+	"pinch":   0.36,
+	"dash":    0.6,
+	"bunch":   100,
+	"sprig":   1,
+	"handful": 30,
+	"package": 450,
+}
+
+type NutrientTotals struct {
+	Calories float64 `json:"calories"`
+	ProteinG float64 `json:"protein_g"`
+	FatG     float64 `json:"fat_g"`
+	CarbsG   float64 `json:"carbs_g"`
+	FiberG   float64 `json:"fiber_g"`
+	SodiumMg float64 `json:"sodium_mg"`
+}
+
+type NutritionLine struct {
+	Name        string
+	Quantity    *float64
+	QuantityMax *float64
+	Unit        string
+}
+
+type NutritionMatch struct {
+	Ingredient string  `json:"ingredient"`
+	MatchedAs  string  `json:"matched_as"`
+	Grams      float64 `json:"grams"`
+}
+
+type NutritionMiss struct {
+	Ingredient string `json:"ingredient"`
+	Reason     string `json:"reason"`
+}
+
+type NutritionReport struct {
+	Servings   int              `json:"servings"`
+	Total      NutrientTotals   `json:"total"`
+	PerServing NutrientTotals   `json:"per_serving"`
+	Matched    []NutritionMatch `json:"matched"`
+	Unmatched  []NutritionMiss  `json:"unmatched"`
+}
+
+// CalculateNutrition estimates the nutrients of a recipe from its ingredient
+// lines and divides them by servings. Ingredients that are not in the
+// dataset, have no quantity, or whose weight cannot be worked out are listed
+// in Unmatched and left out of the totals. Ranges use their midpoint.
+func CalculateNutrition(lines []NutritionLine, servings int) NutritionReport {
+	if servings < 1 {
+		servings = 1
+	}
+	report := NutritionReport{
+		Servings:  servings,
+		Matched:   []NutritionMatch{},
+		Unmatched: []NutritionMiss{},
+	}
+
+	for _, line := range lines {
+		row, ok := lookupNutrients(line.Name)
+		if !ok {
+			report.Unmatched = append(report.Unmatched, NutritionMiss{line.Name, "not in nutrient dataset"})
+			continue
+		}
+		if line.Quantity == nil {
+			report.Unmatched = append(report.Unmatched, NutritionMiss{line.Name, "no quantity"})
+			continue
+		}
+
+		quantity := *line.Quantity
+		if line.QuantityMax != nil {
+			quantity = (quantity + *line.QuantityMax) / 2
+		}
+
+		grams, ok := ingredientGrams(quantity, line.Unit, row)
+		if !ok {
+			report.Unmatched = append(report.Unmatched, NutritionMiss{line.Name, "cannot convert " + describeUnit(line.Unit) + " to grams"})
+			continue
+		}
+
+		factor := grams / 100
+		report.Total.Calories += row.per100g.Calories * factor
+		report.Total.ProteinG += row.per100g.ProteinG * factor
+		report.Total.FatG += row.per100g.FatG * factor
+		report.Total.CarbsG += row.per100g.CarbsG * factor
+		report.Total.FiberG += row.per100g.FiberG * factor
+		report.Total.SodiumMg += row.per100g.SodiumMg * factor
+		report.Matched = append(report.Matched, NutritionMatch{line.Name, row.name, round1(grams)})
+	}
+
+	s := float64(servings)
+	report.PerServing = NutrientTotals{
+		Calories: round1(report.Total.Calories / s),
+		ProteinG: round1(report.Total.ProteinG / s),
+		FatG:     round1(report.Total.FatG / s),
+		CarbsG:   round1(report.Total.CarbsG / s),
+		FiberG:   round1(report.Total.FiberG / s),
+		SodiumMg: round1(report.Total.SodiumMg / s),
+	}
+	report.Total = NutrientTotals{
+		Calories: round1(report.Total.Calories),
+		ProteinG: round1(report.Total.ProteinG),
+		FatG:     round1(report.Total.FatG),
+		CarbsG:   round1(report.Total.CarbsG),
+		FiberG:   round1(report.Total.FiberG),
+		SodiumMg: round1(report.Total.SodiumMg),
+	}
+	return report
+}
+
+func ingredientGrams(quantity float64, unit string, row nutrientRow) (float64, bool) {
+	if perUnit, ok := massUnits[unit]; ok {
+		return quantity * perUnit, true
+	}
+	if perUnit, ok := volumeUnits[unit]; ok {
+		density := row.density
+		if density == 0 {
+			if d, ok := IngredientDensity(row.name); ok {
+				density = d
+			} else {
+				density = 1
+			}
+		}
+		return quantity * perUnit * density, true
+	}
+	if unit == "" && row.gramsEach > 0 {
+		return quantity * row.gramsEach, true
+	}
+	if grams, ok := unitGrams[unit]; ok {
+		if unit == "clove" && row.gramsEach > 0 {
+			grams = row.gramsEach
+		}
+		return quantity * grams, true
+	}
+	return 0, false
+}
+
+// lookupNutrients finds the dataset row whose name appears as whole words in
+// the ingredient name, preferring the longest match so that "peanut butter"
+// wins over "butter". Both sides are normalized, so plurals and synonyms
+// resolve to the same entry.
+func lookupNutrients(name string) (nutrientRow, bool) {
+	nutrientOnce.Do(loadNutrients)
+
+	padded := " " + NormalizeIngredientName(name) + " "
+	var best nutrientRow
+	found := false
+	for key, row := range nutrientTable {
+		if strings.Contains(padded, " "+key+" ") && (!found || len(key) > len(best.name)) {
+			best, found = row, true
+		}
+	}
+	return best, found
+}
+
+func loadNutrients() {
+	records, err := csv.NewReader(bytes.NewReader(nutrientCSV)).ReadAll()
+	if err != nil {
+		panic(fmt.Sprintf("invalid embedded nutrient dataset: %v", err))
+	}
+
+	nutrientTable = make(map[string]nutrientRow, len(records))
+	for i, record := range records {
+		if i == 0 {
+			continue
+		}
+		values := make([]float64, len(record)-1)
+		for j, field := range record[1:] {
+			if field == "" {
+				continue
+			}
+			values[j], err = strconv.ParseFloat(field, 64)
+			if err != nil {
+				panic(fmt.Sprintf("invalid embedded nutrient dataset at line %d: %v", i+1, err))
+			}
+		}
+		key := NormalizeIngredientName(record[0])
+		nutrientTable[key] = nutrientRow{
+			name: key,
+			per100g: NutrientTotals{
+				Calories: values[0],
+				ProteinG: values[1],
+				FatG:     values[2],
+				CarbsG:   values[3],
+				FiberG:   values[4],
+				SodiumMg: values[5],
+			},
+			gramsEach: values[6],
+			density:   values[7],
+		}
+	}
+}
+
+func describeUnit(unit string) string {
+	if unit == "" {
+		return "a count"
+	}
+	return "'" + unit + "'"
+}
+
+func round1(v float64) float64 {
+	return math.Round(v*10) / 10
+}
@@ -0,0 +1,81 @@
+package utils
+
+import "testing"
+
+func TestCalculateNutrition(t *testing.T) {
+	num := func(v float64) *float64 { return &v }
+
+	t.Run("ranges use their midpoint", func(t *testing.T) {
+		report := CalculateNutrition([]NutritionLine{{Name: "eggs", Quantity: num(2), QuantityMax: num(4)}}, 1)
+		if len(report.Matched) != 1 || report.Matched[0].Grams != 150 {
+			t.Fatalf("matched = %+v, want eggs at 150 g", report.Matched)
+		}
+		if report.Total.Calories != 214.5 {
+			t.Errorf("calories = %v, want 214.5", report.Total.Calories)
+		}
+	})
+
+	t.Run("grams from mass, volume and count units", func(t *testing.T) {
+		tests := []struct {
+			line  NutritionLine
+			grams float64
+		}{
+			{NutritionLine{Name: "sugar", Quantity: num(200), Unit: "g"}, 200},
+			{NutritionLine{Name: "sugar", Quantity: num(1), Unit: "kg"}, 1000},
+			{NutritionLine{Name: "milk", Quantity: num(1), Unit: "cup"}, 243.7},
+			{NutritionLine{Name: "garlic", Quantity: num(2), Unit: "clove"}, 10},
+			{NutritionLine{Name: "butter", Quantity: num(1), Unit: "stick"}, 113},
+		}
+		for _, tt := range tests {
+			report := CalculateNutrition([]NutritionLine{tt.line}, 1)
+			if len(report.Matched) != 1 || report.Matched[0].Grams != tt.grams {
+				t.Errorf("%v %s %s: matched = %+v, want %v g", *tt.line.Quantity, tt.line.Unit, tt.line.Name, report.Matched, tt.grams)
+			}
+		}
+	})
+
+	t.Run("unmatched reasons", func(t *testing.T) {
+		report := CalculateNutrition([]NutritionLine{
+			{Name: "unobtainium", Quantity: num(1), Unit: "g"},
+			{Name: "salt"},
+			{Name: "sugar", Quantity: num(2), Unit: "bag"},
+			{Name: "sugar", Quantity: num(2)},
+		}, 1)
+
+		want := []NutritionMiss{
+			{"unobtainium", "not in nutrient dataset"},
+			{"salt", "no quantity"},
+			{"sugar", "cannot convert 'bag' to grams"},
+			{"sugar", "cannot convert a count to grams"},
+		}
+		if len(report.Unmatched) != len(want) {
+			t.Fatalf("unmatched = %+v, want %+v", report.Unmatched, want)
+		}
+		for i := range want {
+			if report.Unmatched[i] != want[i] {
+				t.Errorf("unmatched[%d] = %+v, want %+v", i, report.Unmatched[i], want[i])
+			}
+		}
+		if len(report.Matched) != 0 || report.Total != (NutrientTotals{}) {
+			t.Errorf("unmatched lines counted: matched %+v, total %+v", report.Matched, report.Total)
+		}
+	})
+
+	t.Run("per serving is rounded to one decimal", func(t *testing.T) {
+		report := CalculateNutrition([]NutritionLine{{Name: "butter", Quantity: num(100), Unit: "g"}}, 3)
+		want := NutrientTotals{Calories: 239, ProteinG: 0.3, FatG: 27, CarbsG: 0, FiberG: 0, SodiumMg: 3.7}
+		if report.PerServing != want {
+			t.Errorf("per serving = %+v, want %+v", report.PerServing, want)
+		}
+		if report.Total.Calories != 717 || report.Total.FatG != 81.1 {
+			t.Errorf("total = %+v, want 717 kcal and 81.1 g fat", report.Total)
+		}
+	})
+
+	t.Run("servings below one count as one", func(t *testing.T) {
+		report := CalculateNutrition([]NutritionLine{{Name: "butter", Quantity: num(100), Unit: "g"}}, 0)
+		if report.Servings != 1 || report.PerServing.Calories != 717 {
+			t.Errorf("servings = %d, per serving calories = %v; want 1 and 717", report.Servings, report.PerServing.Calories)
+		}
+	})
+}
@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"os"
+	"strconv"
+)
+
+const (
+	defaultRatingPriorMean   = 3.0
+	defaultRatingPriorWeight = 10.0
+)
+
+// RatingPrior returns the Bayesian prior used for weighted ratings: the mean
+// score assumed for an unrated recipe and how many ratings that assumption is
+// worth. Both can be tuned with RATING_PRIOR_MEAN and RATING_PRIOR_WEIGHT.
+func RatingPrior() (mean, weight float64) {
+	mean, weight = defaultRatingPriorMean, defaultRatingPriorWeight
+	if v := os.Getenv("RATING_PRIOR_MEAN"); v != "" {
+		if parsed, err := strconv.ParseFloat(v, 64); err == nil && parsed >= 1 && parsed <= 5 {
+			mean = parsed
+		}
+	}
+	if v := os.Getenv("RATING_PRIOR_WEIGHT"); v != "" {
+		if parsed, err := strconv.ParseFloat(v, 64); err == nil && parsed >= 0 {
+			weight = parsed
+		}
+	}
+	return mean, weight
+}
+
+// BayesianRating blends a recipe's mean score with the prior so that a few
+// ratings cannot outrank a large body of slightly lower ones.
+func BayesianRating(average float64, count int) float64 {
+	mean, weight := RatingPrior()
+	if weight+float64(count) == 0 {
+		return 0
+	}
+	return (weight*mean + average*float64(count)) / (weight + float64(count))
+}
@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBayesianRating(t *testing.T) {
+	tests := []struct {
+		name        string
+		priorMean   string
+		priorWeight string
+		average     float64
+		count       int
+		want        float64
+	}{
+		{"unrated recipe starts at the prior mean", "", "", 0, 0, 3},
+		{"few ratings are pulled toward the prior", "", "", 5, 2, (10*3.0 + 5*2) / 12},
+		{"many ratings outweigh the prior", "", "", 4.5, 990, (10*3.0 + 4.5*990) / 1000},
+		{"custom prior mean", "4", "", 0, 0, 4},
+		{"custom prior weight", "", "2", 5, 2, (2*3.0 + 5*2) / 4},
+		{"zero weight uses the plain average", "", "0", 4.2, 3, 4.2},
+		{"zero weight and no ratings", "", "0", 0, 0, 0},
+		{"prior mean out of range falls back", "7", "", 0, 0, 3},
+		{"negative prior weight falls back", "", "-1", 5, 10, (10*3.0 + 5*10) / 20},
+		{"unparsable prior falls back", "high", "many", 5, 10, (10*3.0 + 5*10) / 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("RATING_PRIOR_MEAN", tt.priorMean)
+			t.Setenv("RATING_PRIOR_WEIGHT", tt.priorWeight)
+			if got := BayesianRating(tt.average, tt.count); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("BayesianRating(%v, %d) = %v, want %v", tt.average, tt.count, got, tt.want)
+			}
+		})
+	}
+}
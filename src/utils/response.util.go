@@ -19,6 +19,17 @@ type PaginatedResponse struct {
 	TotalCount int64       `json:"total_count"`
 }
 
+// CursorPaginatedResponse is the envelope for keyset-paginated lists. Cursors
+// are opaque; a null cursor means there is no page in that direction.
+type CursorPaginatedResponse struct {
+	Success    bool        `json:"success"`
+	Message    string      `json:"message"`
+	Data       interface{} `json:"data,omitempty"`
+	PerPage    int         `json:"per_page"`
+	NextCursor *string     `json:"next_cursor"`
+	PrevCursor *string     `json:"prev_cursor"`
+}
+
 func SuccessResponse(c *gin.Context, statusCode int, message string, data interface{}) {
 	c.JSON(statusCode, APIResponse{
 		Success: true,
@@ -45,3 +56,19 @@ func PaginatedSuccessResponse(c *gin.Context, statusCode int, message string, da
 		TotalCount: totalCount,
 	})
 }
+
+func CursorPaginatedSuccessResponse(c *gin.Context, statusCode int, message string, data interface{}, perPage int, nextCursor, prevCursor string) {
+	response := CursorPaginatedResponse{
+		Success: true,
+		Message: message,
+		Data:    data,
+		PerPage: perPage,
+	}
+	if nextCursor != "" {
+		response.NextCursor = &nextCursor
+	}
+	if prevCursor != "" {
+		response.PrevCursor = &prevCursor
+	}
+	c.JSON(statusCode, response)
+}
@@ -0,0 +1,9 @@
+package utils
+
+import "strings"
+
+// Slugify turns a display name into a URL-friendly key: "Gluten Free" becomes
+// "gluten-free".
+func Slugify(name string) string {
+	return strings.ReplaceAll(CanonicalIngredientName(name), " ", "-")
+}
@@ -0,0 +1,172 @@
+package utils
+
+import (
+	"sort"
+	"strings"
+)
+
+// ingredientSynonyms maps a canonical ingredient name to the regional names
+// and spellings that mean the same thing. Entries are in stemmed (singular)
+// form, as produced by singularPhrase.
+var ingredientSynonyms = map[string][]string{
+	"all purpose flour": {"plain flour", "ap flour"},
+	"arugula":           {"rocket", "roquette"},
+	"baking soda":       {"bicarbonate of soda", "bicarb soda", "sodium bicarbonate"},
+	"beet":              {"beetroot"},
+	"bell pepper":       {"capsicum", "sweet pepper"},
+	"chickpea":          {"garbanzo bean", "garbanzo", "chick pea"},
+	"cilantro":          {"coriander leaf", "fresh coriander"},
+	"cornstarch":        {"cornflour", "corn starch"},
+	"eggplant":          {"aubergine", "brinjal"},
+	"green onion":       {"scallion", "spring onion"},
+	"ground beef":       {"minced beef", "beef mince"},
+	"heavy cream":       {"double cream", "whipping cream"},
+	"powdered sugar":    {"icing sugar", "confectioner sugar"},
+	"rutabaga":          {"swede"},
+	"shrimp":            {"prawn"},
+	"snow pea":          {"mangetout"},
+	"zucchini":          {"courgette"},
+}
+
+// synonymVariants lists every variant with its canonical name, longest
+// variant first so "spring onion" is replaced before a shorter entry could
+// claim part of it.
+var synonymVariants = func() [][2]string {
+	var variants [][2]string
+	for canonical, names := range ingredientSynonyms {
+		for _, name := range names {
+			variants = append(variants, [2]string{name, canonical})
+		}
+	}
+	sort.Slice(variants, func(i, j int) bool {
+		if len(variants[i][0]) != len(variants[j][0]) {
+			return len(variants[i][0]) > len(variants[j][0])
+		}
+		return variants[i][0] < variants[j][0]
+	})
+	return variants
+}()
+
+// invariantWords end in "s" without being plurals.
+var invariantWords = map[string]bool{
+	"asparagus": true, "couscous": true, "hummus": true, "molasses": true,
+	"swiss": true, "citrus": true, "grits": true,
+}
+
+// NormalizeIngredientName reduces an ingredient name to the form used for
+// matching: canonicalized, stemmed to singular and with synonyms replaced by
+// their canonical name, so "Scallions" and "spring onion" both become
+// "green onion".
+func NormalizeIngredientName(name string) string {
+	return replaceSynonyms(singularPhrase(CanonicalIngredientName(name)))
+}
+
+func replaceSynonyms(phrase string) string {
+	padded := " " + phrase + " "
+	for _, v := range synonymVariants {
+		padded = strings.ReplaceAll(padded, " "+v[0]+" ", " "+v[1]+" ")
+	}
+	return strings.TrimSpace(padded)
+}
+
+// singularPhrase folds simple English plurals word by word:
+// "tomatoes" → "tomato", "berries" → "berry", "peaches" → "peach".
+func singularPhrase(phrase string) string {
+	words := strings.Fields(phrase)
+	for i, w := range words {
+		switch {
+		case invariantWords[w]:
+		case len(w) > 4 && strings.HasSuffix(w, "ies"):
+			words[i] = w[:len(w)-3] + "y"
+		case len(w) > 4 && (strings.HasSuffix(w, "oes") || strings.HasSuffix(w, "ches") ||
+			strings.HasSuffix(w, "shes") || strings.HasSuffix(w, "xes")):
+			words[i] = w[:len(w)-2]
+		case len(w) > 3 && strings.HasSuffix(w, "s") &&
+			!strings.HasSuffix(w, "ss") && !strings.HasSuffix(w, "us"):
+			words[i] = w[:len(w)-1]
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// IngredientVocabulary collects the words of the given normalized ingredient
+// names plus every word in the synonym dictionary, for typo correction.
+func IngredientVocabulary(names []string) map[string]bool {
+	vocabulary := make(map[string]bool)
+	add := func(phrase string) {
+		for _, w := range strings.Fields(phrase) {
+			vocabulary[w] = true
+		}
+	}
+	for _, name := range names {
+		add(name)
+	}
+	for _, v := range synonymVariants {
+		add(v[0])
+		add(v[1])
+	}
+	return vocabulary
+}
+
+// CorrectIngredientTerm normalizes a search term and replaces each word that
+// is not in the vocabulary with the closest word within a small edit
+// distance (one edit for words of 4-7 letters, two for longer ones). It
+// reports whether any word was corrected.
+func CorrectIngredientTerm(term string, vocabulary map[string]bool) (string, bool) {
+	words := strings.Fields(singularPhrase(CanonicalIngredientName(term)))
+	corrected := false
+	for i, w := range words {
+		if vocabulary[w] {
+			continue
+		}
+		if best, ok := closestWord(w, vocabulary); ok {
+			words[i] = best
+			corrected = true
+		}
+	}
+	return replaceSynonyms(strings.Join(words, " ")), corrected
+}
+
+func closestWord(word string, vocabulary map[string]bool) (string, bool) {
+	maxEdits := 0
+	switch n := len([]rune(word)); {
+	case n >= 8:
+		maxEdits = 2
+	case n >= 4:
+		maxEdits = 1
+	}
+	if maxEdits == 0 {
+		return "", false
+	}
+
+	best, bestDistance := "", maxEdits+1
+	for candidate := range vocabulary {
+		d := editDistance(word, candidate)
+		if d < bestDistance || (d == bestDistance && candidate < best) {
+			best, bestDistance = candidate, d
+		}
+	}
+	return best, bestDistance <= maxEdits
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,107 @@
+package utils
+
+import "testing"
+
+func TestNormalizeIngredientName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Tomatoes", "tomato"},
+		{"berries", "berry"},
+		{"Peaches", "peach"},
+		{"glass noodles", "glass noodle"},
+		{"asparagus", "asparagus"},
+		{"hummus", "hummus"},
+		{"Scallions", "green onion"},
+		{"spring onion", "green onion"},
+		{"aubergines", "eggplant"},
+		{"Icing Sugar", "powdered sugar"},
+		{"plain flour", "all purpose flour"},
+		{"chick peas", "chickpea"},
+
+		// The longest variant wins, so "garbanzo beans" does not become
+		// "chickpea bean" through the shorter "garbanzo".
+		{"garbanzo beans", "chickpea"},
+		{"garbanzo", "chickpea"},
+
+		// Synonyms only match whole words.
+		{"swedes", "rutabaga"},
+		{"swedish meatballs", "swedish meatball"},
+	}
+	for _, tt := range tests {
+		if got := NormalizeIngredientName(tt.name); got != tt.want {
+			t.Errorf("NormalizeIngredientName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestIngredientVocabulary(t *testing.T) {
+	vocabulary := IngredientVocabulary([]string{"tomato", "olive oil"})
+
+	for _, word := range []string{"tomato", "olive", "oil", "scallion", "green", "onion", "zucchini", "courgette"} {
+		if !vocabulary[word] {
+			t.Errorf("vocabulary is missing %q", word)
+		}
+	}
+	for _, word := range []string{"olive oil", "green onion", "garlic"} {
+		if vocabulary[word] {
+			t.Errorf("vocabulary should not contain %q", word)
+		}
+	}
+}
+
+func TestCorrectIngredientTerm(t *testing.T) {
+	vocabulary := IngredientVocabulary([]string{"tomato", "garlic", "olive oil", "butter"})
+
+	tests := []struct {
+		term          string
+		want          string
+		wantCorrected bool
+	}{
+		{"tomato", "tomato", false},
+		{"Tomatoes", "tomato", false},
+		{"tomatoe", "tomato", true},
+		{"garlik", "garlic", true},
+		{"butterr", "butter", true},
+		{"zuchini", "zucchini", true},
+		{"olive oyl", "olive oyl", false}, // three letters: too short to correct
+		{"xyzzyx", "xyzzyx", false},       // nothing within one edit
+
+		// Words of eight or more letters allow two edits, and a corrected
+		// synonym is still mapped to its canonical name.
+		{"courgete", "zucchini", true},
+		{"scallion", "green onion", false},
+	}
+	for _, tt := range tests {
+		got, corrected := CorrectIngredientTerm(tt.term, vocabulary)
+		if got != tt.want || corrected != tt.wantCorrected {
+			t.Errorf("CorrectIngredientTerm(%q) = %q, %v; want %q, %v", tt.term, got, corrected, tt.want, tt.wantCorrected)
+		}
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"", "abc", 3},
+		{"abc", "", 3},
+		{"tomato", "tomato", 0},
+		{"tomato", "tomatoe", 1},
+		{"garlik", "garlic", 1},
+		{"flaw", "lawn", 2},
+		{"kitten", "sitting", 3},
+		{"crème", "creme", 1}, // runes, not bytes
+	}
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := editDistance(tt.b, tt.a); got != tt.want {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.b, tt.a, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,22 @@
+package utils
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+const defaultTrashRetentionDays = 30
+
+// TrashRetention is how long a deleted recipe stays restorable before the
+// purge job removes it for good. TRASH_RETENTION_DAYS overrides the default
+// of 30 days; 0 purges on the next run.
+func TrashRetention() time.Duration {
+	days := defaultTrashRetentionDays
+	if v := os.Getenv("TRASH_RETENTION_DAYS"); v != "" {
+		if parsed, err := strconv.Atoi(v); err == nil && parsed >= 0 {
+			days = parsed
+		}
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
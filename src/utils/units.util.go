@@ -0,0 +1,171 @@
+package utils
+
+import (
+	"math"
+	"strings"
+)
+
+const (
+	SystemOriginal = ""
+	SystemMetric   = "metric"
+	SystemUS       = "us"
+)
+
+// Base units are millilitres for volume and grams for mass.
+var volumeUnits = map[string]float64{
+	"tsp":   4.92892,
+	"tbsp":  14.7868,
+	"fl oz": 29.5735,
+	"cup":   236.588,
+	"pt":    473.176,
+	"qt":    946.353,
+	"gal":   3785.41,
+	"ml":    1,
+	"l":     1000,
+}
+
+var massUnits = map[string]float64{
+	"mg": 0.001,
+	"g":  1,
+	"kg": 1000,
+	"oz": 28.3495,
+	"lb": 453.592,
+}
+
+// ingredientDensities holds grams per millilitre for common dry or solid
+// ingredients that metric cooks weigh rather than measure by volume. Keys are
+// canonical ingredient names; the longest key found as whole words in an
+// ingredient name wins, so "all purpose flour" beats "flour".
+var ingredientDensities = map[string]float64{
+	"flour":             0.53,
+	"all purpose flour": 0.53,
+	"bread flour":       0.55,
+	"whole wheat flour": 0.51,
+	"almond flour":      0.41,
+	"cornstarch":        0.54,
+	"cornmeal":          0.64,
+	"sugar":             0.85,
+	"granulated sugar":  0.85,
+	"caster sugar":      0.85,
+	"brown sugar":       0.93,
+	"powdered sugar":    0.56,
+	"icing sugar":       0.56,
+	"butter":            0.96,
+	"cocoa powder":      0.42,
+	"rolled oats":       0.41,
+	"oats":              0.41,
+	"rice":              0.85,
+	"salt":              1.2,
+	"kosher salt":       0.65,
+	"baking powder":     0.9,
+	"baking soda":       1.1,
+	"honey":             1.42,
+	"maple syrup":       1.32,
+	"peanut butter":     1.08,
+	"grated parmesan":   0.42,
+	"chocolate chips":   0.72,
+	"raisins":           0.61,
+	"breadcrumbs":       0.45,
+}
+
+func IsVolumeUnit(unit string) bool {
+	_, ok := volumeUnits[unit]
+	return ok
+}
+
+func IsMassUnit(unit string) bool {
+	_, ok := massUnits[unit]
+	return ok
+}
+
+// IngredientDensity returns the grams-per-millilitre density for an
+// ingredient name, if the built-in table knows it.
+func IngredientDensity(name string) (float64, bool) {
+	padded := " " + CanonicalIngredientName(name) + " "
+	best, bestLen := 0.0, 0
+	for key, density := range ingredientDensities {
+		if len(key) > bestLen && strings.Contains(padded, " "+key+" ") {
+			best, bestLen = density, len(key)
+		}
+	}
+	return best, bestLen > 0
+}
+
+// ConvertUnit picks the target unit for an ingredient measured as quantity
+// unit in the given measurement system and returns the multiplier to apply to
+// its quantities. Volumes of ingredients in the density table are converted to
+// grams for the metric system. Units outside the tables (clove, can, pinch...)
+// are returned unchanged with a multiplier of 1.
+func ConvertUnit(quantity float64, unit, ingredient, system string) (float64, string) {
+	if system == SystemOriginal {
+		return 1, unit
+	}
+
+	if perUnit, ok := volumeUnits[unit]; ok {
+		ml := quantity * perUnit
+		if system == SystemMetric {
+			if density, ok := IngredientDensity(ingredient); ok {
+				target := metricMassUnit(ml * density)
+				return perUnit * density / massUnits[target], target
+			}
+			target := metricVolumeUnit(ml)
+			return perUnit / volumeUnits[target], target
+		}
+		target := usVolumeUnit(ml)
+		return perUnit / volumeUnits[target], target
+	}
+
+	if perUnit, ok := massUnits[unit]; ok {
+		grams := quantity * perUnit
+		target := metricMassUnit(grams)
+		if system == SystemUS {
+			target = usMassUnit(grams)
+		}
+		return perUnit / massUnits[target], target
+	}
+
+	return 1, unit
+}
+
+func metricVolumeUnit(ml float64) string {
+	if ml >= 1000 {
+		return "l"
+	}
+	return "ml"
+}
+
+func metricMassUnit(grams float64) string {
+	if grams >= 1000 {
+		return "kg"
+	}
+	return "g"
+}
+
+func usVolumeUnit(ml float64) string {
+	switch {
+	case ml < volumeUnits["tbsp"]:
+		return "tsp"
+	case ml < volumeUnits["cup"]/4:
+		return "tbsp"
+	case ml < volumeUnits["gal"]:
+		return "cup"
+	default:
+		return "gal"
+	}
+}
+
+func usMassUnit(grams float64) string {
+	if grams >= massUnits["lb"] {
+		return "lb"
+	}
+	return "oz"
+}
+
+// RoundQuantity rounds to a precision a cook would use: whole grams and
+// millilitres for larger amounts, otherwise up to two decimals.
+func RoundQuantity(quantity float64, unit string) float64 {
+	if (unit == "g" || unit == "ml") && quantity >= 10 {
+		return math.Round(quantity)
+	}
+	return math.Round(quantity*100) / 100
+}
@@ -0,0 +1,118 @@
+package utils
+
+import (
+	"math"
+	"testing"
+)
+
+func TestUnitTables(t *testing.T) {
+	tests := []struct {
+		name  string
+		table map[string]float64
+		from  string
+		count float64
+		to    string
+	}{
+		{"3 tsp make a tbsp", volumeUnits, "tsp", 3, "tbsp"},
+		{"16 tbsp make a cup", volumeUnits, "tbsp", 16, "cup"},
+		{"2 fl oz make a quarter cup", volumeUnits, "fl oz", 8, "cup"},
+		{"2 cups make a pint", volumeUnits, "cup", 2, "pt"},
+		{"2 pints make a quart", volumeUnits, "pt", 2, "qt"},
+		{"4 quarts make a gallon", volumeUnits, "qt", 4, "gal"},
+		{"1000 ml make a litre", volumeUnits, "ml", 1000, "l"},
+		{"1000 mg make a gram", massUnits, "mg", 1000, "g"},
+		{"1000 g make a kilogram", massUnits, "g", 1000, "kg"},
+		{"16 oz make a pound", massUnits, "oz", 16, "lb"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.count * tt.table[tt.from] / tt.table[tt.to]
+			if math.Abs(got-1) > 0.001 {
+				t.Errorf("%v %s = %v %s, want 1", tt.count, tt.from, got, tt.to)
+			}
+		})
+	}
+}
+
+func TestConvertUnit(t *testing.T) {
+	tests := []struct {
+		quantity   float64
+		unit       string
+		ingredient string
+		system     string
+		want       float64
+		wantUnit   string
+	}{
+		{2, "cup", "all-purpose flour", SystemMetric, 2 * 236.588 * 0.53, "g"},
+		{1, "cup", "milk", SystemMetric, 236.588, "ml"},
+		{5, "cup", "water", SystemMetric, 5 * 0.236588, "l"},
+		{6, "cup", "sugar", SystemMetric, 6 * 236.588 * 0.85 / 1000, "kg"},
+		{1, "lb", "ground beef", SystemMetric, 453.592, "g"},
+		{1500, "g", "potatoes", SystemMetric, 1.5, "kg"},
+		{4, "tsp", "vanilla", SystemUS, 4 * 4.92892 / 14.7868, "tbsp"},
+		{500, "ml", "stock", SystemUS, 500 / 236.588, "cup"},
+		{5, "l", "water", SystemUS, 5000 / 3785.41, "gal"},
+		{2, "kg", "chicken", SystemUS, 2000 / 453.592, "lb"},
+		{100, "g", "butter", SystemUS, 100 / 28.3495, "oz"},
+		{2, "clove", "garlic", SystemMetric, 2, "clove"},
+		{2, "cup", "flour", SystemOriginal, 2, "cup"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.system+" "+tt.unit+" "+tt.ingredient, func(t *testing.T) {
+			multiplier, unit := ConvertUnit(tt.quantity, tt.unit, tt.ingredient, tt.system)
+			got := tt.quantity * multiplier
+			if unit != tt.wantUnit || math.Abs(got-tt.want) > 1e-6 {
+				t.Errorf("ConvertUnit(%v, %q, %q, %q) gives %v %s, want %v %s",
+					tt.quantity, tt.unit, tt.ingredient, tt.system, got, unit, tt.want, tt.wantUnit)
+			}
+		})
+	}
+}
+
+func TestIngredientDensity(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    float64
+		wantHit bool
+	}{
+		{"flour", 0.53, true},
+		{"Bread Flour", 0.55, true},
+		{"whole-wheat flour", 0.51, true},
+		{"light brown sugar", 0.93, true},
+		{"kosher salt", 0.65, true},
+		{"milk", 0, false},
+		{"flourless cake", 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := IngredientDensity(tt.name)
+			if ok != tt.wantHit || got != tt.want {
+				t.Errorf("IngredientDensity(%q) = %v, %v; want %v, %v", tt.name, got, ok, tt.want, tt.wantHit)
+			}
+		})
+	}
+}
+
+func TestRoundQuantity(t *testing.T) {
+	tests := []struct {
+		quantity float64
+		unit     string
+		want     float64
+	}{
+		{250.78, "g", 251},
+		{473.2, "ml", 473},
+		{9.876, "g", 9.88},
+		{1.3333, "cup", 1.33},
+		{250.78, "cup", 250.78},
+		{0.004, "tsp", 0},
+	}
+
+	for _, tt := range tests {
+		if got := RoundQuantity(tt.quantity, tt.unit); got != tt.want {
+			t.Errorf("RoundQuantity(%v, %q) = %v, want %v", tt.quantity, tt.unit, got, tt.want)
+		}
+	}
+}